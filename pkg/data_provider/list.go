@@ -0,0 +1,15 @@
+package data_provider
+
+import "sort"
+
+// ListProviderTags returns the tags of all registered providers, sorted.
+func (m *DataManager) ListProviderTags() []string {
+	m.pm.RLock()
+	defer m.pm.RUnlock()
+	tags := make([]string, 0, len(m.ps))
+	for tag := range m.ps {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,72 @@
+package macaddr
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"sync/atomic"
+)
+
+// AtomicGroup is a group of matchers that is replaced as a whole.
+// Unlike a LocalMatcherGroup of DynamicMatcher, whose members update
+// independently, a reader never sees a half-updated group: Match reads
+// an immutable snapshot of the members lock-free, and Rebuild swaps in
+// a new snapshot.
+type AtomicGroup struct {
+	ms atomic.Pointer[[]LocalMatcher]
+}
+
+// NewAtomicGroup creates an AtomicGroup with the members ms.
+func NewAtomicGroup(ms ...LocalMatcher) *AtomicGroup {
+	g := new(AtomicGroup)
+	g.Rebuild(ms)
+	return g
+}
+
+// Rebuild replaces all members of the group with ms. ms is copied, so the
+// caller may reuse the slice. The replaced members are not closed, since
+// they may be reused in ms, the caller closes them when they are no longer
+// needed.
+func (g *AtomicGroup) Rebuild(ms []LocalMatcher) {
+	snapshot := slices.Clone(ms)
+	g.ms.Store(&snapshot)
+}
+
+func (g *AtomicGroup) load() []LocalMatcher {
+	if p := g.ms.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+func (g *AtomicGroup) Match(mac net.HardwareAddr) bool {
+	for _, m := range g.load() {
+		if m.Match(mac) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *AtomicGroup) Len() int {
+	s := 0
+	for _, m := range g.load() {
+		s += m.Len()
+	}
+	return s
+}
+
+// Close closes the current members of the group.
+func (g *AtomicGroup) Close() error {
+	var errs []error
+	for _, m := range g.load() {
+		if err := m.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (g *AtomicGroup) Kind() string { return "atomic_group" }
+
+var _ LocalMatcher = (*AtomicGroup)(nil)
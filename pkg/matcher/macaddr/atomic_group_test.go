@@ -0,0 +1,66 @@
+package macaddr
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicGroup(t *testing.T) {
+	a := newTestMatcher(t, "00:11:22:33:44:01")
+	b := newTestMatcher(t, "00:11:22:33:44:02")
+	c := newTestMatcher(t, "00:11:22:33:44:03", "00:11:22:33:44:04")
+
+	g := NewAtomicGroup(a, b)
+	if !g.Match(mustParseMAC("00:11:22:33:44:02")) || g.Len() != 2 {
+		t.Fatalf("Match() = false or Len() = %d, want a match and 2", g.Len())
+	}
+
+	ms := []LocalMatcher{c}
+	g.Rebuild(ms)
+	ms[0] = a // Rebuild must copy ms.
+	if g.Match(mustParseMAC("00:11:22:33:44:01")) {
+		t.Error("members replaced by Rebuild should not match")
+	}
+	if !g.Match(mustParseMAC("00:11:22:33:44:04")) || g.Len() != 2 {
+		t.Errorf("Match() = false or Len() = %d, want a match and 2", g.Len())
+	}
+
+	if new(AtomicGroup).Match(mustParseMAC("00:11:22:33:44:01")) {
+		t.Error("zero AtomicGroup should not match")
+	}
+}
+
+func TestAtomicGroup_Concurrent(t *testing.T) {
+	// Both members hold the same MAC, so every snapshot must match it.
+	mac := mustParseMAC("00:11:22:33:44:55")
+	snapshots := [][]LocalMatcher{
+		{newTestMatcher(t, "00:11:22:33:44:55")},
+		{newTestMatcher(t, "aa:bb:cc:dd:ee:ff"), newTestMatcher(t, "00:11:22:33:44:55")},
+	}
+	g := NewAtomicGroup(snapshots[0]...)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if !g.Match(mac) {
+					t.Error("group should match in every snapshot")
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		g.Rebuild(snapshots[i%2])
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+package macaddr
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// binaryMagic is the header of the binary MAC file format.
+// The format is:
+//
+//	magic [4]byte "MACB"
+//	count uint32, big endian
+//	count * 6 raw EUI-48 bytes
+var binaryMagic = [4]byte{'M', 'A', 'C', 'B'}
+
+const binaryHeaderLen = 8
+
+// isBinaryMacFile reports whether in starts with the binary format magic.
+func isBinaryMacFile(in []byte) bool {
+	return len(in) >= len(binaryMagic) && [4]byte(in) == binaryMagic
+}
+
+// ParseBinaryMacFile parses MAC addresses in the binary format written by
+// Matcher.WriteBinary.
+func ParseBinaryMacFile(in []byte) (*Matcher, error) {
+	if !isBinaryMacFile(in) {
+		return nil, errors.New("invalid binary mac file, bad magic")
+	}
+	if len(in) < binaryHeaderLen {
+		return nil, fmt.Errorf("invalid binary mac file, truncated header, got %d bytes", len(in))
+	}
+	count := binary.BigEndian.Uint32(in[4:8])
+	data := in[binaryHeaderLen:]
+	if want := uint64(count) * 6; uint64(len(data)) != want {
+		return nil, fmt.Errorf("invalid binary mac file, header claims %d entries (%d bytes), got %d bytes", count, want, len(data))
+	}
+
+	m := &Matcher{macs: make(map[[6]byte]struct{}, count)}
+	for i := 0; i < len(data); i += 6 {
+		m.add(data[i : i+6])
+	}
+	return m, nil
+}
+
+// WriteBinary writes the EUI-48 addresses of the matcher to w in the
+// binary format, sorted in ascending order. It returns an error if the
+// matcher has entries the format cannot represent, e.g. EUI-64 addresses
+// or patterns.
+func (m *Matcher) WriteBinary(w io.Writer) error {
+	if n := m.Len() - len(m.macs); n > 0 {
+		return fmt.Errorf("binary format only supports EUI-48 addresses, matcher has %d other entries", n)
+	}
+
+	keys := m.Entries()
+	bw := bufio.NewWriter(w)
+	var header [binaryHeaderLen]byte
+	copy(header[:], binaryMagic[:])
+	binary.BigEndian.PutUint32(header[4:], uint32(len(keys)))
+	if _, err := bw.Write(header[:]); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if _, err := bw.Write(k[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
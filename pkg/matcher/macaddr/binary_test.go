@@ -0,0 +1,57 @@
+package macaddr
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBinaryMacFile_RoundTrip(t *testing.T) {
+	m, err := ParseTextMacFile([]byte("00:11:22:33:44:55\naa:bb:cc:dd:ee:ff\n66-77-88-99-AA-BB\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := new(bytes.Buffer)
+	if err := m.WriteBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != binaryHeaderLen+3*6 {
+		t.Fatalf("unexpected binary size %d", b.Len())
+	}
+
+	bm, err := ParseBinaryMacFile(b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bm.Len() != m.Len() {
+		t.Fatalf("Len() = %d, want %d", bm.Len(), m.Len())
+	}
+	for k := range m.macs {
+		if !bm.Match(k[:]) {
+			t.Errorf("%x should match", k)
+		}
+	}
+
+	// The provider parser sniffs the magic.
+	pm, err := parseMacFile(b.Bytes(), nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pm.Len() != m.Len() {
+		t.Errorf("parseMacFile() Len() = %d, want %d", pm.Len(), m.Len())
+	}
+
+	// Truncated files return errors.
+	for _, n := range []int{2, 6, b.Len() - 1} {
+		if _, err := ParseBinaryMacFile(b.Bytes()[:n]); err == nil {
+			t.Errorf("truncated file of %d bytes should fail", n)
+		}
+	}
+	if _, err := ParseBinaryMacFile(append(b.Bytes(), 0)); err == nil {
+		t.Error("file with trailing data should fail")
+	}
+
+	if err := newTestMatcher(t, "aa:bb:cc").WriteBinary(new(bytes.Buffer)); err == nil {
+		t.Error("WriteBinary() with a prefix entry should fail")
+	}
+}
@@ -0,0 +1,60 @@
+package macaddr
+
+import (
+	"fmt"
+	"net"
+)
+
+// Flag bits of the first octet of a MAC address, see IEEE 802.
+const (
+	// BitGroup is the I/G (individual/group) bit, the least significant
+	// bit of the first octet. It is set for multicast and broadcast
+	// addresses.
+	BitGroup byte = 0x01
+	// BitLocal is the U/L (universal/local) bit, the second least
+	// significant bit of the first octet. It is set for locally
+	// administered addresses, e.g. the randomized addresses of phones,
+	// and clear for addresses burned in by the vendor.
+	BitLocal byte = 0x02
+)
+
+// MatchLocallyAdministered reports whether the U/L bit of mac is set, see
+// BitLocal. Such addresses are often randomized or spoofed.
+func MatchLocallyAdministered(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&BitLocal != 0
+}
+
+// MatchMulticast reports whether the I/G bit of mac is set, see BitGroup.
+func MatchMulticast(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&BitGroup != 0
+}
+
+// BitMatcher is a LocalMatcher that matches addresses by the bits of their
+// first octet: mac matches if mac[0]&mask == value. For example,
+// NewBitMatcher(BitLocal|BitGroup, BitLocal) matches all randomized
+// (locally administered unicast) addresses. It holds no entries, so its
+// Len is 0.
+type BitMatcher struct {
+	mask, value byte
+}
+
+// NewBitMatcher creates a BitMatcher. It returns an error if value has
+// bits outside mask, as it could never match.
+func NewBitMatcher(mask, value byte) (*BitMatcher, error) {
+	if value&^mask != 0 {
+		return nil, fmt.Errorf("value %#02x has bits outside mask %#02x", value, mask)
+	}
+	return &BitMatcher{mask: mask, value: value}, nil
+}
+
+func (b *BitMatcher) Match(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&b.mask == b.value
+}
+
+func (b *BitMatcher) Len() int     { return 0 }
+func (b *BitMatcher) Close() error { return nil }
+
+// Kind returns "bit", see LocalMatcher.
+func (b *BitMatcher) Kind() string { return "bit" }
+
+var _ LocalMatcher = (*BitMatcher)(nil)
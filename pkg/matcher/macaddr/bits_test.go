@@ -0,0 +1,56 @@
+package macaddr
+
+import "testing"
+
+func TestBitPredicates(t *testing.T) {
+	tests := []struct {
+		mac       string
+		local     bool
+		multicast bool
+	}{
+		{"00:1b:63:84:45:e6", false, false}, // burned in
+		{"da:a1:19:3c:5e:01", true, false},  // randomized
+		{"01:00:5e:00:00:01", false, true},
+		{"ff:ff:ff:ff:ff:ff", true, true},
+	}
+	for _, tt := range tests {
+		mac := mustParseMAC(tt.mac)
+		if got := MatchLocallyAdministered(mac); got != tt.local {
+			t.Errorf("MatchLocallyAdministered(%s) = %v, want %v", tt.mac, got, tt.local)
+		}
+		if got := MatchMulticast(mac); got != tt.multicast {
+			t.Errorf("MatchMulticast(%s) = %v, want %v", tt.mac, got, tt.multicast)
+		}
+	}
+	if MatchLocallyAdministered(nil) || MatchMulticast(nil) {
+		t.Error("empty address should not match")
+	}
+}
+
+func TestBitMatcher(t *testing.T) {
+	m, err := NewBitMatcher(BitLocal|BitGroup, BitLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		mac  string
+		want bool
+	}{
+		{"da:a1:19:3c:5e:01", true},  // randomized
+		{"00:1b:63:84:45:e6", false}, // burned in
+		{"03:00:00:00:00:01", false}, // locally administered multicast
+		{"da:a1:19:3c:5e:01:02:03", true},
+	}
+	for _, tt := range tests {
+		if got := m.Match(mustParseMAC(tt.mac)); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.mac, got, tt.want)
+		}
+	}
+	if m.Match(nil) {
+		t.Error("empty address should not match")
+	}
+
+	if _, err := NewBitMatcher(BitLocal, BitGroup); err == nil {
+		t.Error("NewBitMatcher() should reject a value outside the mask")
+	}
+}
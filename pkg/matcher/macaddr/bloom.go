@@ -0,0 +1,85 @@
+package macaddr
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+const (
+	bloomBitsPerEntry = 10 // ~1% false positive rate with bloomHashes hashes
+	bloomHashes       = 7
+)
+
+// bloomFilter is a fixed size Bloom filter over EUI-48 keys.
+// It never reports false negatives.
+type bloomFilter struct {
+	seed uint64
+	bits []uint64
+	m    uint64 // number of bits
+}
+
+func newBloomFilter(expectedN int) *bloomFilter {
+	m := uint64(max(expectedN, 1)) * bloomBitsPerEntry
+	m = (m + 63) / 64 * 64
+	return &bloomFilter{
+		seed: rand.Uint64(),
+		bits: make([]uint64, m/64),
+		m:    m,
+	}
+}
+
+// hashes returns the two base hashes for double hashing.
+func (f *bloomFilter) hashes(key [6]byte) (h1, h2 uint64) {
+	h := mix64(mac48ToUint64(key[:]) ^ f.seed)
+	h1 = h & math.MaxUint32
+	h2 = h>>32 | 1
+	return h1, h2
+}
+
+// mix64 is the splitmix64 finalizer. It is cheaper than a general purpose
+// hash for a fixed 48-bit input.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (f *bloomFilter) add(key [6]byte) {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < bloomHashes; i++ {
+		b := (h1 + i*h2) % f.m
+		f.bits[b/64] |= 1 << (b % 64)
+	}
+}
+
+// mayContain returns false if key was definitely never added.
+func (f *bloomFilter) mayContain(key [6]byte) bool {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < bloomHashes; i++ {
+		b := (h1 + i*h2) % f.m
+		if f.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// NewMatcherBloom creates a new empty Matcher whose exact EUI-48 lookups
+// are fronted by a Bloom filter sized for expectedN entries.
+// The filter costs about 10 bits (1.25 bytes) per expected entry on top of
+// the map and has a ~1% false positive rate, which grows if more than
+// expectedN entries are added. A negative answer of the filter skips the
+// map lookup, a positive one is always confirmed by the map, so the
+// filter never produces false matches or false negatives. The filter is
+// much smaller than the map, so it can cut the cost of misses on very large
+// lists whose map does not fit in CPU caches; measure with
+// BenchmarkMatcherBloom_Miss500k before enabling it. Removed entries stay
+// in the filter and only cost an extra map lookup.
+func NewMatcherBloom(expectedN int) *Matcher {
+	m := NewMatcherSize(expectedN)
+	m.bloom = newBloomFilter(expectedN)
+	return m
+}
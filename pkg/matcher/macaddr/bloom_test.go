@@ -0,0 +1,60 @@
+package macaddr
+
+import (
+	"testing"
+)
+
+func TestMatcherBloom(t *testing.T) {
+	m := NewMatcherBloom(1000)
+	for i := 0; i < 1000; i++ {
+		key := uint64ToMAC48(uint64(i) * 3)
+		m.add(key[:])
+	}
+	if err := m.Add("aa:bb:cc", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// No false negatives.
+	for i := 0; i < 1000; i++ {
+		key := uint64ToMAC48(uint64(i) * 3)
+		if !m.Match(key[:]) {
+			t.Fatalf("%x should match", key)
+		}
+	}
+	// No false positives, the map confirms every filter hit.
+	for i := 0; i < 1000; i++ {
+		key := uint64ToMAC48(uint64(i)*3 + 1)
+		if m.Match(key[:]) {
+			t.Fatalf("%x should not match", key)
+		}
+	}
+	// Patterns are still consulted on a filter miss.
+	if !m.Match(mustParseMAC("aa:bb:cc:00:00:01")) {
+		t.Error("prefix entry should match")
+	}
+}
+
+func benchmarkMatcherMiss(b *testing.B, m *Matcher) {
+	const n = 500000
+	for i := 0; i < n; i++ {
+		key := uint64ToMAC48(uint64(i) * 2)
+		m.add(key[:])
+	}
+	macs := make([][]byte, 1024)
+	for i := range macs {
+		key := uint64ToMAC48(uint64(i)*977*2 + 1) // never added
+		macs[i] = key[:]
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Match(macs[i%len(macs)])
+	}
+}
+
+func BenchmarkMatcher_Miss500k(b *testing.B) {
+	benchmarkMatcherMiss(b, NewMatcher())
+}
+
+func BenchmarkMatcherBloom_Miss500k(b *testing.B) {
+	benchmarkMatcherMiss(b, NewMatcherBloom(500000))
+}
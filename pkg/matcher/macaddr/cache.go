@@ -0,0 +1,56 @@
+package macaddr
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SetCacheFile makes Update write the raw data of every successful update
+// to path, so it can be loaded by LoadCachedOrEmpty after a restart even
+// if the source is unreachable. An empty path disables the cache.
+func (d *DynamicMatcher) SetCacheFile(path string) {
+	d.l.Lock()
+	defer d.l.Unlock()
+	d.cacheFile = path
+}
+
+// LoadCachedOrEmpty loads the data cached at path by SetCacheFile. If the
+// cache does not exist, the matcher is left as is and nil is returned.
+// If the cache cannot be read or parsed, the matcher is left as is and an
+// error is returned. The cache is not rewritten.
+func (d *DynamicMatcher) LoadCachedOrEmpty(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return d.update(b)
+}
+
+// writeFileAtomic writes b to a temp file next to path and renames it to
+// path, so path never holds partial data.
+func writeFileAtomic(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package macaddr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDynamicMatcher_Cache(t *testing.T) {
+	parse := func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "macs.cache")
+
+	d := NewDynamicMatcher(parse)
+	d.SetCacheFile(path)
+	if err := d.Update([]byte("00:11:22:33:44:55\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Update([]byte("bad\n")); err == nil {
+		t.Fatal("expected parse error")
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "00:11:22:33:44:55\n" {
+		t.Fatalf("cache = %q, %v, want the last good data", b, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temp files should be cleaned up, got %d files", len(entries))
+	}
+
+	restarted := NewDynamicMatcher(parse)
+	if err := restarted.LoadCachedOrEmpty(path); err != nil {
+		t.Fatal(err)
+	}
+	if !restarted.Match(mustParseMAC("00:11:22:33:44:55")) {
+		t.Error("matcher should be seeded from the cache")
+	}
+
+	empty := NewDynamicMatcher(parse)
+	if err := empty.LoadCachedOrEmpty(filepath.Join(dir, "missing")); err != nil || empty.Len() != 0 {
+		t.Errorf("LoadCachedOrEmpty() = %v, Len() = %d, want an empty matcher", err, empty.Len())
+	}
+}
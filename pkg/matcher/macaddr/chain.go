@@ -0,0 +1,83 @@
+package macaddr
+
+import (
+	"errors"
+	"net"
+)
+
+// ChainLink is a single layer of a ChainMatcher.
+type ChainLink struct {
+	// Tag is reported by ChainMatcher.MatchChain when this link matches.
+	Tag     string
+	Matcher LocalMatcher
+	// StopOnMatch stops the evaluation after this link matches.
+	// If false, the evaluation falls through to the next link even on a hit.
+	StopOnMatch bool
+}
+
+// ChainMatcher evaluates its links in the order they were appended.
+// A link that misses always passes the evaluation to the next link.
+// A link that matches records its tag, then either stops the chain
+// (StopOnMatch) or falls through to the next link.
+type ChainMatcher struct {
+	links []ChainLink
+}
+
+// NewChainMatcher creates a ChainMatcher from links.
+func NewChainMatcher(links ...ChainLink) *ChainMatcher {
+	return &ChainMatcher{links: links}
+}
+
+// Append appends a link to the end of the chain.
+func (c *ChainMatcher) Append(l ChainLink) {
+	c.links = append(c.links, l)
+}
+
+// MatchChain evaluates the chain and returns the tags of all matched links
+// in evaluation order. ok is true if at least one link matched.
+func (c *ChainMatcher) MatchChain(mac net.HardwareAddr) (tags []string, ok bool) {
+	for _, l := range c.links {
+		if !l.Matcher.Match(mac) {
+			continue
+		}
+		tags = append(tags, l.Tag)
+		if l.StopOnMatch {
+			break
+		}
+	}
+	return tags, len(tags) > 0
+}
+
+// Match reports whether any link matches mac. It short-circuits on the
+// first hit, since stop/continue only affects which tags are reported.
+func (c *ChainMatcher) Match(mac net.HardwareAddr) bool {
+	for _, l := range c.links {
+		if l.Matcher.Match(mac) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ChainMatcher) Len() int {
+	s := 0
+	for _, l := range c.links {
+		s += l.Matcher.Len()
+	}
+	return s
+}
+
+// Close closes all matchers in the chain.
+func (c *ChainMatcher) Close() error {
+	var errs []error
+	for _, l := range c.links {
+		if err := l.Matcher.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *ChainMatcher) Kind() string { return "chain" }
+
+var _ LocalMatcher = (*ChainMatcher)(nil)
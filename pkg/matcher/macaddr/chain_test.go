@@ -0,0 +1,83 @@
+package macaddr
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+// newTestMatcher builds a Matcher from macs and fails t on any invalid entry.
+func newTestMatcher(t testing.TB, macs ...string) *Matcher {
+	t.Helper()
+	m := NewMatcher()
+	if err := BatchLoad(m, macs); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func mustParseMAC(s string) net.HardwareAddr {
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		panic(err)
+	}
+	return mac
+}
+
+func TestChainMatcher(t *testing.T) {
+	a := newTestMatcher(t, "00:11:22:33:44:55", "00:11:22:33:44:66")
+	b := newTestMatcher(t, "00:11:22:33:44:55", "aa:bb:cc:dd:ee:ff")
+	c := newTestMatcher(t, "aa:bb:cc:dd:ee:ff", "66:77:88:99:aa:bb")
+
+	tests := []struct {
+		name     string
+		links    []ChainLink
+		mac      string
+		wantTags []string
+	}{
+		{
+			name:     "stop on first hit",
+			links:    []ChainLink{{"a", a, true}, {"b", b, true}},
+			mac:      "00:11:22:33:44:55",
+			wantTags: []string{"a"},
+		},
+		{
+			name:     "fall through on hit",
+			links:    []ChainLink{{"a", a, false}, {"b", b, true}, {"c", c, true}},
+			mac:      "00:11:22:33:44:55",
+			wantTags: []string{"a", "b"},
+		},
+		{
+			name:     "fall through on miss",
+			links:    []ChainLink{{"a", a, true}, {"b", b, true}, {"c", c, true}},
+			mac:      "aa:bb:cc:dd:ee:ff",
+			wantTags: []string{"b"},
+		},
+		{
+			name:     "last link only",
+			links:    []ChainLink{{"a", a, true}, {"b", b, true}, {"c", c, true}},
+			mac:      "66:77:88:99:aa:bb",
+			wantTags: []string{"c"},
+		},
+		{
+			name:     "no hit",
+			links:    []ChainLink{{"a", a, true}, {"b", b, false}, {"c", c, true}},
+			mac:      "01:02:03:04:05:06",
+			wantTags: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := NewChainMatcher(tt.links...)
+			mac := mustParseMAC(tt.mac)
+			tags, ok := cm.MatchChain(mac)
+			if !reflect.DeepEqual(tags, tt.wantTags) {
+				t.Errorf("MatchChain() tags = %v, want %v", tags, tt.wantTags)
+			}
+			if want := len(tt.wantTags) > 0; ok != want || cm.Match(mac) != want {
+				t.Errorf("MatchChain() ok = %v, Match() = %v, want %v", ok, cm.Match(mac), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package macaddr
+
+// Clear removes all entries and patterns from the matcher, keeping the
+// storage it allocated, so reloading a list of similar size into it does
+// not allocate again. Options like strict mode, the bloom filter and the
+// observer are kept.
+// Like Remove, it must not be called while the matcher is being matched.
+func (m *Matcher) Clear() {
+	clear(m.macs)
+	clear(m.macs64)
+	clear(m.macs20)
+	clear(m.prefixes)
+	m.masked = m.masked[:0]
+	m.ranges.ranges = m.ranges.ranges[:0]
+	m.ranges.maxHi = m.ranges.maxHi[:0]
+	clear(m.meta)
+	clear(m.tagIndex)
+	if m.bloom != nil {
+		clear(m.bloom.bits)
+	}
+}
@@ -0,0 +1,53 @@
+package macaddr
+
+import "testing"
+
+func TestMatcher_Clear(t *testing.T) {
+	patterns := []string{
+		"00:11:22:33:44:55",
+		"00:11:22:33:44:55:66:77",
+		"aa:bb:cc",
+		"de:ad:*:*:*:*",
+		"10:00:00:00:00:00-10:00:00:00:00:ff",
+	}
+	probes := []string{
+		"00:11:22:33:44:55",
+		"00:11:22:33:44:55:66:77",
+		"aa:bb:cc:01:02:03",
+		"de:ad:be:ef:00:01",
+		"10:00:00:00:00:10",
+	}
+	for _, m := range []*Matcher{NewMatcher(), NewMatcherBloom(8)} {
+		if err := m.AddMany(patterns...); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.AddWithTag("00:11:22:33:44:66", "tv"); err != nil {
+			t.Fatal(err)
+		}
+
+		m.Clear()
+		if m.Len() != 0 {
+			t.Errorf("Len() after Clear() = %d, want 0", m.Len())
+		}
+		for _, p := range probes {
+			if m.Match(mustParseMAC(p)) {
+				t.Errorf("Match(%s) after Clear() = true, want false", p)
+			}
+		}
+		if macs := m.MACsForTag("tv"); len(macs) != 0 {
+			t.Errorf("MACsForTag() after Clear() = %v, want none", macs)
+		}
+
+		if err := m.AddMany(patterns...); err != nil {
+			t.Fatal(err)
+		}
+		if m.Len() != len(patterns) {
+			t.Errorf("Len() after re-adding = %d, want %d", m.Len(), len(patterns))
+		}
+		for _, p := range probes {
+			if !m.Match(mustParseMAC(p)) {
+				t.Errorf("Match(%s) after re-adding = false, want true", p)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package macaddr
+
+import (
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/pmkol/mosdns-x/pkg/lru"
+)
+
+// ClientMACCache caches client IP to MAC mappings resolved by
+// ResolveClientMAC, so repeated queries of a busy client do not look up
+// the neighbor table each time. It holds up to size entries for ttl each,
+// and evicts the least recently used entry when it is full. Lookup
+// errors, e.g. ErrNeighborNotFound, are not cached.
+// It is safe for concurrent use.
+type ClientMACCache struct {
+	ttl     time.Duration
+	resolve func(ip net.IP) (net.HardwareAddr, error)
+	now     func() time.Time
+
+	mu  sync.Mutex
+	lru *lru.LRU[netip.Addr, clientMACEntry]
+}
+
+type clientMACEntry struct {
+	mac      net.HardwareAddr
+	expireAt time.Time
+}
+
+// NewClientMACCache creates a ClientMACCache. size must be positive.
+func NewClientMACCache(size int, ttl time.Duration) *ClientMACCache {
+	return &ClientMACCache{
+		ttl:     ttl,
+		resolve: ResolveClientMAC,
+		now:     time.Now,
+		lru:     lru.NewLRU[netip.Addr, clientMACEntry](size, nil),
+	}
+}
+
+// Get returns the MAC address of ip, from the cache if its entry has not
+// expired, otherwise from ResolveClientMAC.
+func (c *ClientMACCache) Get(ip net.IP) (net.HardwareAddr, error) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return nil, ErrNeighborNotFound
+	}
+	addr = addr.Unmap()
+
+	now := c.now()
+	c.mu.Lock()
+	e, ok := c.lru.Get(addr)
+	c.mu.Unlock()
+	if ok && now.Before(e.expireAt) {
+		return e.mac, nil
+	}
+
+	mac, err := c.resolve(ip)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.lru.Add(addr, clientMACEntry{mac: mac, expireAt: now.Add(c.ttl)})
+	c.mu.Unlock()
+	return mac, nil
+}
+
+// Len returns the number of cached entries, including expired ones.
+func (c *ClientMACCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,70 @@
+package macaddr
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClientMACCache(t *testing.T) {
+	table := map[string]string{
+		"192.168.1.2": "00:11:22:33:44:02",
+		"192.168.1.3": "00:11:22:33:44:03",
+		"192.168.1.4": "00:11:22:33:44:04",
+	}
+	lookups := make(map[string]int)
+	now := time.Unix(1000, 0)
+	c := NewClientMACCache(2, time.Minute)
+	c.now = func() time.Time { return now }
+	c.resolve = func(ip net.IP) (net.HardwareAddr, error) {
+		lookups[ip.String()]++
+		s, ok := table[ip.String()]
+		if !ok {
+			return nil, ErrNeighborNotFound
+		}
+		return mustParseMAC(s), nil
+	}
+	get := func(ip, want string) {
+		t.Helper()
+		mac, err := c.Get(net.ParseIP(ip))
+		if err != nil || mac.String() != want {
+			t.Fatalf("Get(%s) = %v, %v, want %s", ip, mac, err, want)
+		}
+	}
+
+	// Hit.
+	get("192.168.1.2", "00:11:22:33:44:02")
+	get("192.168.1.2", "00:11:22:33:44:02")
+	if lookups["192.168.1.2"] != 1 {
+		t.Errorf("cache hit should not resolve again, lookups = %d", lookups["192.168.1.2"])
+	}
+
+	// Refresh on expiry.
+	table["192.168.1.2"] = "00:11:22:33:44:22"
+	now = now.Add(time.Minute)
+	get("192.168.1.2", "00:11:22:33:44:22")
+	if lookups["192.168.1.2"] != 2 {
+		t.Errorf("expired entry should be resolved again, lookups = %d", lookups["192.168.1.2"])
+	}
+
+	// Eviction of the least recently used entry.
+	get("192.168.1.3", "00:11:22:33:44:03")
+	get("192.168.1.2", "00:11:22:33:44:22")
+	get("192.168.1.4", "00:11:22:33:44:04")
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+	get("192.168.1.2", "00:11:22:33:44:22")
+	get("192.168.1.3", "00:11:22:33:44:03")
+	if lookups["192.168.1.2"] != 2 || lookups["192.168.1.3"] != 2 {
+		t.Errorf("only the least recently used entry should be evicted, lookups = %v", lookups)
+	}
+
+	// Errors are not cached.
+	if _, err := c.Get(net.ParseIP("192.168.1.9")); err != ErrNeighborNotFound {
+		t.Errorf("Get() error = %v, want ErrNeighborNotFound", err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("errors should not be cached, Len() = %d", c.Len())
+	}
+}
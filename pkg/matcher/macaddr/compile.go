@@ -0,0 +1,19 @@
+package macaddr
+
+import "io"
+
+// CompileTextToBinary reads a text mac file from in, see
+// LoadFromTextReader, and writes its entries to out in the binary format
+// read by ParseBinaryMacFile, which loads faster. It stops at the first
+// bad line, and fails if the list has entries the binary format cannot
+// represent, e.g. prefixes. It returns the number of entries written.
+func CompileTextToBinary(in io.Reader, out io.Writer) (int, error) {
+	m := NewMatcher()
+	if err := LoadFromTextReader(m, in); err != nil {
+		return 0, err
+	}
+	if err := m.WriteBinary(out); err != nil {
+		return 0, err
+	}
+	return len(m.macs), nil
+}
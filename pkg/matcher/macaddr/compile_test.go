@@ -0,0 +1,47 @@
+package macaddr
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompileTextToBinary(t *testing.T) {
+	const data = `
+# phones
+00:11:22:33:44:55
+AA-BB-CC-DD-EE-FF # tv
+0011.2233.4466
+`
+	var buf bytes.Buffer
+	n, err := CompileTextToBinary(strings.NewReader(data), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("CompileTextToBinary() = %d, want 3", n)
+	}
+
+	m, err := ParseBinaryMacFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ParseTextMacFile([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(m.Entries(), want.Entries()) {
+		t.Errorf("round trip entries = %v, want %v", m.Entries(), want.Entries())
+	}
+
+	tests := []string{
+		"00:11:22:33:44:55\nnot a mac\n",
+		"aa:bb:cc\n",
+	}
+	for _, data := range tests {
+		if _, err := CompileTextToBinary(strings.NewReader(data), new(bytes.Buffer)); err == nil {
+			t.Errorf("CompileTextToBinary(%q) should fail", data)
+		}
+	}
+}
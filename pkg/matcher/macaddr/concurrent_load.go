@@ -0,0 +1,86 @@
+package macaddr
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pmkol/mosdns-x/pkg/data_provider"
+)
+
+// BatchLoadMacProviderConcurrent is like BatchLoadMacProviderSites, but
+// loads the entries with a scheme, e.g. "provider:", in parallel with at
+// most workers loads at a time. workers <= 0 means no limit.
+// The matchers are appended to the group in the order of e, so the result
+// does not depend on scheduling. On the first error, loads that have not
+// started yet are skipped, the loaded ones are detached, and the error is
+// returned.
+func BatchLoadMacProviderConcurrent(
+	e []string,
+	dm *data_provider.DataManager,
+	sites []string,
+	workers int,
+) (*LocalMatcherGroup, error) {
+	sf := newSiteFilter(sites)
+	mg := &LocalMatcherGroup{}
+	staticMatcher := NewMatcher()
+	mg.Append(staticMatcher)
+
+	type source struct {
+		s, scheme, ref string
+	}
+	var sources []source
+	for _, s := range e {
+		scheme, ref, ok := cutScheme(s)
+		if !ok {
+			if err := loadSite(staticMatcher, s, sf, ""); err != nil {
+				return nil, fmt.Errorf("failed to load data %s: %w", s, err)
+			}
+			continue
+		}
+		sources = append(sources, source{s: s, scheme: scheme, ref: ref})
+	}
+
+	type result struct {
+		m      LocalMatcher
+		closer func()
+	}
+	results := make([]result, len(sources))
+	g, ctx := errgroup.WithContext(context.Background())
+	if workers > 0 {
+		g.SetLimit(workers)
+	}
+	for i, src := range sources {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			m, closer, err := loadSource(src.s, src.scheme, src.ref, dm, sf, 0)
+			if err != nil {
+				return err
+			}
+			results[i] = result{m: m, closer: closer}
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	for _, r := range results {
+		if r.m == nil {
+			continue
+		}
+		if fm, ok := r.m.(*Matcher); ok && staticMatcher.addFrom(fm) {
+			continue
+		}
+		mg.Append(r.m)
+		if r.closer != nil {
+			mg.AppendCloser(r.closer)
+		}
+	}
+	if err != nil {
+		_ = mg.Close()
+		return nil, err
+	}
+	return mg, nil
+}
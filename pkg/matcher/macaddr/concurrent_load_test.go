@@ -0,0 +1,59 @@
+package macaddr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/pmkol/mosdns-x/pkg/data_provider"
+)
+
+func TestBatchLoadMacProviderConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	dm := data_provider.NewDataManager()
+	e := []string{"00:11:22:33:44:55"}
+	wantLens := []int{1}
+	for i := range 8 {
+		var b strings.Builder
+		for j := range i + 1 {
+			fmt.Fprintf(&b, "02:00:00:00:%02x:%02x\n", i, j)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("p%d.txt", i))
+		if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p, err := data_provider.NewDataProvider(zap.NewNop(), data_provider.DataProviderConfig{File: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag := fmt.Sprintf("p%d", i)
+		dm.AddDataProvider(tag, p)
+		e = append(e, "provider:"+tag)
+		wantLens = append(wantLens, i+1)
+	}
+
+	for _, workers := range []int{0, 1, 3} {
+		mg, err := BatchLoadMacProviderConcurrent(e, dm, nil, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mg.Len() != 37 {
+			t.Errorf("workers %d: Len() = %d, want 37", workers, mg.Len())
+		}
+		if got := mg.PerMatcherLen(); !reflect.DeepEqual(got, wantLens) {
+			t.Errorf("workers %d: PerMatcherLen() = %v, want %v", workers, got, wantLens)
+		}
+		if err := mg.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := BatchLoadMacProviderConcurrent(append(e, "provider:missing"), dm, nil, 2); err == nil {
+		t.Error("expected error for a missing provider")
+	}
+}
@@ -0,0 +1,27 @@
+package macaddr
+
+import "net"
+
+// MatchNone is a LocalMatcher that matches nothing. It is useful as a
+// placeholder, e.g. for an empty source.
+type MatchNone struct{}
+
+func (MatchNone) Match(net.HardwareAddr) bool { return false }
+func (MatchNone) Len() int                    { return 0 }
+func (MatchNone) Close() error                { return nil }
+func (MatchNone) Kind() string                { return "none" }
+
+// MatchAll is a LocalMatcher that matches every MAC address. It is useful
+// as a catch-all tail, e.g. in a ChainMatcher. It holds no entries, so its
+// Len is 0.
+type MatchAll struct{}
+
+func (MatchAll) Match(net.HardwareAddr) bool { return true }
+func (MatchAll) Len() int                    { return 0 }
+func (MatchAll) Close() error                { return nil }
+func (MatchAll) Kind() string                { return "all" }
+
+var (
+	_ LocalMatcher = MatchNone{}
+	_ LocalMatcher = MatchAll{}
+)
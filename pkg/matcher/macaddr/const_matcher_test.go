@@ -0,0 +1,27 @@
+package macaddr
+
+import "testing"
+
+func TestMatchNone(t *testing.T) {
+	var m LocalMatcher = MatchNone{}
+	if m.Match(mustParseMAC("00:11:22:33:44:55")) || m.Len() != 0 || m.Close() != nil {
+		t.Error("MatchNone should match nothing and be empty")
+	}
+}
+
+func TestMatchAll(t *testing.T) {
+	var m LocalMatcher = MatchAll{}
+	for _, s := range []string{"00:11:22:33:44:55", "ff:ff:ff:ff:ff:ff", "00:11:22:33:44:55:66:77"} {
+		if !m.Match(mustParseMAC(s)) {
+			t.Errorf("MatchAll should match %s", s)
+		}
+	}
+	if m.Close() != nil {
+		t.Error("Close() should not fail")
+	}
+
+	c := NewChainMatcher(ChainLink{"none", MatchNone{}, true}, ChainLink{"all", m, true})
+	if tags, _ := c.MatchChain(mustParseMAC("00:11:22:33:44:55")); len(tags) != 1 || tags[0] != "all" {
+		t.Errorf("MatchChain() tags = %v, want [all]", tags)
+	}
+}
@@ -0,0 +1,158 @@
+package macaddr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// defaultDebugDumpLimit is the number of dumped lines of DebugHandler if
+// the request has no limit.
+const defaultDebugDumpLimit = 1000
+
+// maxDebugMatchBody is the maximum size of the body of a match request.
+const maxDebugMatchBody = 1024
+
+var errDumpLimit = errors.New("dump limit reached")
+
+// DebugHandler returns a handler for live troubleshooting.
+// GET returns Len and the entries of the matcher, see Dump. The dump stops
+// after the number of lines of the "limit" query, 1000 by default, and 0
+// disables the limit. POST reports whether the MAC address in the body
+// matches, as "true" or "false".
+// The handler exposes the MAC addresses of the matcher, so only mount it
+// on a trusted listener.
+func (m *Matcher) DebugHandler() http.Handler {
+	return debugHandler(m, m.Dump)
+}
+
+// DebugHandler is like Matcher.DebugHandler.
+func (d *DynamicMatcher) DebugHandler() http.Handler {
+	return debugHandler(d, d.Dump)
+}
+
+// DebugHandler is like Matcher.DebugHandler. The entries of each matcher
+// of the group are dumped in a section, see LocalMatcher.Kind.
+func (mg *LocalMatcherGroup) DebugHandler() http.Handler {
+	return debugHandler(mg, mg.dumpSections)
+}
+
+// dumpSections writes the entries of the matchers of the group, each after
+// a comment line naming it. Matchers that cannot be dumped are noted.
+func (mg *LocalMatcherGroup) dumpSections(w io.Writer) error {
+	for i, m := range mg.g {
+		if _, err := fmt.Fprintf(w, "# matcher %d (%s)\n", i, m.Kind()); err != nil {
+			return err
+		}
+		if err := dumpOrNote(w, m); err != nil {
+			return err
+		}
+	}
+	for i, m := range mg.exclude {
+		if _, err := fmt.Fprintf(w, "# exclude %d (%s)\n", i, m.Kind()); err != nil {
+			return err
+		}
+		if err := dumpOrNote(w, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dumpOrNote(w io.Writer, m LocalMatcher) error {
+	if dm, ok := m.(dumper); ok {
+		return dm.Dump(w)
+	}
+	_, err := fmt.Fprintf(w, "# %T does not support Dump\n", m)
+	return err
+}
+
+func debugHandler(m LocalMatcher, dump func(w io.Writer) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			serveDebugDump(w, r, m, dump)
+		case http.MethodPost:
+			serveDebugMatch(w, r, m)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func serveDebugDump(w http.ResponseWriter, r *http.Request, m LocalMatcher, dump func(w io.Writer) error) {
+	limit := defaultDebugDumpLimit
+	if s := r.URL.Query().Get("limit"); len(s) > 0 {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", s), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	b := new(bytes.Buffer)
+	fmt.Fprintf(b, "# len: %d\n", m.Len())
+	var dw io.Writer = b
+	if limit > 0 {
+		dw = &lineLimitWriter{w: b, left: limit}
+	}
+	err := dump(dw)
+	if errors.Is(err, errDumpLimit) {
+		fmt.Fprintf(b, "# truncated after %d lines\n", limit)
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = b.WriteTo(w)
+}
+
+func serveDebugMatch(w http.ResponseWriter, r *http.Request, m LocalMatcher) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxDebugMatchBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ok, err := matchString(m, string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = io.WriteString(w, strconv.FormatBool(ok)+"\n")
+}
+
+// lineLimitWriter writes up to left lines to w, then fails with
+// errDumpLimit if there is more data.
+type lineLimitWriter struct {
+	w    io.Writer
+	left int
+}
+
+func (l *lineLimitWriter) Write(p []byte) (int, error) {
+	if l.left <= 0 && len(p) > 0 {
+		return 0, errDumpLimit
+	}
+	n := 0
+	for l.left > 0 {
+		i := bytes.IndexByte(p[n:], '\n')
+		if i < 0 {
+			break
+		}
+		n += i + 1
+		l.left--
+	}
+	if l.left > 0 || n == len(p) {
+		return l.w.Write(p)
+	}
+	written, err := l.w.Write(p[:n])
+	if err == nil {
+		err = errDumpLimit
+	}
+	return written, err
+}
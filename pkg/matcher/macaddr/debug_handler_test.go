@@ -0,0 +1,64 @@
+package macaddr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	m := newTestMatcher(t, "00:11:22:33:44:01", "00:11:22:33:44:02", "aa:bb:cc")
+	h := m.DebugHandler()
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/", "")
+	want := "# len: 3\n00:11:22:33:44:01\n00:11:22:33:44:02\naa:bb:cc\n"
+	if rec.Code != http.StatusOK || rec.Body.String() != want {
+		t.Errorf("GET = %d %q, want 200 %q", rec.Code, rec.Body.String(), want)
+	}
+	rec = do(http.MethodGet, "/?limit=3", "")
+	if rec.Body.String() != want {
+		t.Errorf("GET with limit of all lines = %q, want %q", rec.Body.String(), want)
+	}
+	rec = do(http.MethodGet, "/?limit=1", "")
+	want = "# len: 3\n00:11:22:33:44:01\n# truncated after 1 lines\n"
+	if rec.Body.String() != want {
+		t.Errorf("GET with limit = %q, want %q", rec.Body.String(), want)
+	}
+	if rec = do(http.MethodGet, "/?limit=x", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET with invalid limit = %d, want 400", rec.Code)
+	}
+
+	for body, want := range map[string]string{"00:11:22:33:44:02\n": "true\n", "aa:bb:cc:00:00:01": "true\n", "66:77:88:99:aa:bb": "false\n"} {
+		if rec = do(http.MethodPost, "/", body); rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("POST %q = %d %q, want 200 %q", body, rec.Code, rec.Body.String(), want)
+		}
+	}
+	if rec = do(http.MethodPost, "/", "bad"); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST with invalid mac = %d, want 400", rec.Code)
+	}
+	if rec = do(http.MethodDelete, "/", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE = %d, want 405", rec.Code)
+	}
+}
+
+func TestLocalMatcherGroup_DebugHandler(t *testing.T) {
+	mg := &LocalMatcherGroup{}
+	mg.Append(newTestMatcher(t, "00:11:22:33:44:01"))
+	mg.Append(MatchNone{})
+	mg.AppendExclude(newTestMatcher(t, "00:11:22:33:44:02"))
+
+	rec := httptest.NewRecorder()
+	mg.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	want := "# len: 1\n# matcher 0 (static)\n00:11:22:33:44:01\n# matcher 1 (none)\n" +
+		"# macaddr.MatchNone does not support Dump\n# exclude 0 (static)\n00:11:22:33:44:02\n"
+	if rec.Body.String() != want {
+		t.Errorf("GET = %q, want %q", rec.Body.String(), want)
+	}
+}
@@ -0,0 +1,93 @@
+package macaddr
+
+import (
+	"fmt"
+	"net"
+)
+
+// DeviceTypeUnknown is returned by DeviceClassifier.DeviceType when no rule matches.
+const DeviceTypeUnknown = "unknown"
+
+// DeviceRule classifies devices into Type. All set conditions of a rule
+// must be met for it to match.
+type DeviceRule struct {
+	Type string `yaml:"type"`
+	// OUIs are 3-octet vendor prefixes, e.g. "b8:27:eb". Empty means any vendor.
+	OUIs []string `yaml:"ouis"`
+	// Randomized requires a locally administered (randomized) unicast MAC.
+	Randomized bool `yaml:"randomized"`
+	// NoHostname requires the device to have no known hostname.
+	NoHostname bool `yaml:"no_hostname"`
+}
+
+// DefaultDeviceRules returns the built-in heuristics. Randomized MACs
+// without a hostname are typically phones using private addresses.
+func DefaultDeviceRules() []DeviceRule {
+	return []DeviceRule{
+		{Type: "mobile", Randomized: true, NoHostname: true},
+	}
+}
+
+type deviceRule struct {
+	typ        string
+	ouis       map[[3]byte]struct{}
+	randomized bool
+	noHostname bool
+}
+
+// DeviceClassifier classifies devices by vendor prefix and MAC bit
+// heuristics. Rules are evaluated in order and the first match wins.
+type DeviceClassifier struct {
+	rules []deviceRule
+}
+
+// NewDeviceClassifier creates a DeviceClassifier from rules.
+// Callers that want to extend the built-ins can append to DefaultDeviceRules.
+func NewDeviceClassifier(rules []DeviceRule) (*DeviceClassifier, error) {
+	c := &DeviceClassifier{rules: make([]deviceRule, 0, len(rules))}
+	for i, r := range rules {
+		if len(r.Type) == 0 {
+			return nil, fmt.Errorf("rule #%d: missing type", i)
+		}
+		dr := deviceRule{typ: r.Type, randomized: r.Randomized, noHostname: r.NoHostname}
+		if len(r.OUIs) > 0 {
+			dr.ouis = make(map[[3]byte]struct{}, len(r.OUIs))
+			for _, s := range r.OUIs {
+				p, err := parsePartialMAC(s)
+				if err != nil {
+					return nil, fmt.Errorf("rule #%d: %w", i, err)
+				}
+				if len(p) != 3 {
+					return nil, fmt.Errorf("rule #%d: invalid OUI %s: expect 3 octets, got %d", i, s, len(p))
+				}
+				dr.ouis[[3]byte(p)] = struct{}{}
+			}
+		}
+		c.rules = append(c.rules, dr)
+	}
+	return c, nil
+}
+
+// DeviceType returns the type of the first rule that matches the device,
+// or DeviceTypeUnknown. hostname may be empty if it is not known.
+func (c *DeviceClassifier) DeviceType(mac net.HardwareAddr, hostname string) string {
+	if len(mac) != 6 {
+		return DeviceTypeUnknown
+	}
+	randomized := MatchLocallyAdministered(mac) && !MatchMulticast(mac)
+	for _, r := range c.rules {
+		if r.ouis != nil {
+			if _, ok := r.ouis[[3]byte(mac)]; !ok {
+				continue
+			}
+		}
+		if r.randomized && !randomized {
+			continue
+		}
+		if r.noHostname && len(hostname) > 0 {
+			continue
+		}
+		return r.typ
+	}
+	return DeviceTypeUnknown
+}
@@ -0,0 +1,45 @@
+package macaddr
+
+import (
+	"testing"
+)
+
+func TestDeviceClassifier_DeviceType(t *testing.T) {
+	rules := append([]DeviceRule{
+		{Type: "printer", OUIs: []string{"00:00:48", "00:1b:a9"}},
+		{Type: "pi", OUIs: []string{"b8:27:eb"}},
+	}, DefaultDeviceRules()...)
+	c, err := NewDeviceClassifier(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		mac      string
+		hostname string
+		want     string
+	}{
+		{"printer oui", "00:1b:a9:01:02:03", "", "printer"},
+		{"printer oui with hostname", "00:00:48:01:02:03", "office-printer", "printer"},
+		{"pi oui", "b8:27:eb:01:02:03", "pi", "pi"},
+		{"randomized no hostname", "da:a1:19:01:02:03", "", "mobile"},
+		{"randomized with hostname", "da:a1:19:01:02:03", "laptop", DeviceTypeUnknown},
+		{"burned in no hostname", "00:11:22:33:44:55", "", DeviceTypeUnknown},
+		{"multicast", "03:00:00:00:00:01", "", DeviceTypeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.DeviceType(mustParseMAC(tt.mac), tt.hostname); got != tt.want {
+				t.Errorf("DeviceType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewDeviceClassifier([]DeviceRule{{OUIs: []string{"00:00:48"}}}); err == nil {
+		t.Error("rule without type should fail")
+	}
+	if _, err := NewDeviceClassifier([]DeviceRule{{Type: "x", OUIs: []string{"00:00"}}}); err == nil {
+		t.Error("rule with invalid OUI should fail")
+	}
+}
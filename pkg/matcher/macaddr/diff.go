@@ -0,0 +1,74 @@
+package macaddr
+
+import (
+	"bytes"
+	"slices"
+)
+
+// MatcherDiff returns the EUI-48 addresses that are in newM but not in oldM
+// (added), and those in oldM but not in newM (removed), both sorted in
+// ascending order. A nil matcher is treated as empty.
+func MatcherDiff(oldM, newM *Matcher) (added, removed [][6]byte) {
+	var oldMacs, newMacs map[[6]byte]struct{}
+	if oldM != nil {
+		oldMacs = oldM.macs
+	}
+	if newM != nil {
+		newMacs = newM.macs
+	}
+	for k := range newMacs {
+		if _, ok := oldMacs[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	for k := range oldMacs {
+		if _, ok := newMacs[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	cmp := func(a, b [6]byte) int { return bytes.Compare(a[:], b[:]) }
+	slices.SortFunc(added, cmp)
+	slices.SortFunc(removed, cmp)
+	return added, removed
+}
+
+// IsSubsetOf reports whether every entry of m is also an entry of other.
+// Entries are compared as stored: exact addresses and prefixes by value,
+// wildcard patterns and ranges as written, not by the addresses they
+// cover. It returns false early if m has more entries of a kind than
+// other, so it is O(n) over the smaller set, plus a linear search of the
+// patterns of other for each pattern of m.
+func (m *Matcher) IsSubsetOf(other *Matcher) bool {
+	return mapSubset(m.macs, other.macs) &&
+		mapSubset(m.macs64, other.macs64) &&
+		mapSubset(m.macs20, other.macs20) &&
+		mapSubset(m.prefixes, other.prefixes) &&
+		sliceSubset(m.masked, other.masked) &&
+		sliceSubset(m.ranges.ranges, other.ranges.ranges)
+}
+
+// IsSupersetOf reports whether other.IsSubsetOf(m).
+func (m *Matcher) IsSupersetOf(other *Matcher) bool {
+	return other.IsSubsetOf(m)
+}
+
+func mapSubset[K comparable](a, b map[K]struct{}) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func sliceSubset[E comparable](a, b []E) bool {
+	for _, e := range a {
+		if !slices.Contains(b, e) {
+			return false
+		}
+	}
+	return true
+}
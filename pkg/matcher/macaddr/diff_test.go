@@ -0,0 +1,72 @@
+package macaddr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcherDiff(t *testing.T) {
+	old := newTestMatcher(t, "00:11:22:33:44:01", "00:11:22:33:44:02", "00:11:22:33:44:03")
+	next := newTestMatcher(t, "00:11:22:33:44:01", "00:11:22:33:44:03", "00:11:22:33:44:04")
+
+	added, removed := MatcherDiff(old, next)
+	if want := [][6]byte{{0x00, 0x11, 0x22, 0x33, 0x44, 0x04}}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+	if want := [][6]byte{{0x00, 0x11, 0x22, 0x33, 0x44, 0x02}}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+
+	if added, removed := MatcherDiff(nil, old); len(added) != 3 || len(removed) != 0 {
+		t.Errorf("diff from nil = %v, %v, want 3 added", added, removed)
+	}
+}
+
+func TestDynamicMatcher_OnDiff(t *testing.T) {
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	var calls int
+	var added, removed [][6]byte
+	d.OnDiff = func(a, r [][6]byte) {
+		calls++
+		added, removed = a, r
+	}
+
+	if err := d.Update([]byte("00:11:22:33:44:01\n00:11:22:33:44:02\n00:11:22:33:44:03\n")); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("OnDiff should not be called on the first load, got %d calls", calls)
+	}
+	if err := d.Update([]byte("00:11:22:33:44:01\n00:11:22:33:44:03\n00:11:22:33:44:04\n")); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || len(added) != 1 || len(removed) != 1 {
+		t.Errorf("OnDiff got %d calls, added %v, removed %v, want 1 call, 1 added, 1 removed", calls, added, removed)
+	}
+}
+
+func TestMatcher_IsSubsetOf(t *testing.T) {
+	known := newTestMatcher(t, "00:11:22:33:44:01", "00:11:22:33:44:02", "aa:bb:cc", "aa:*:cc:dd:ee:ff")
+	tests := []struct {
+		name      string
+		m         *Matcher
+		wantSub   bool
+		wantSuper bool
+	}{
+		{"proper subset", newTestMatcher(t, "00:11:22:33:44:02", "aa:*:cc:dd:ee:ff"), true, false},
+		{"equal", newTestMatcher(t, "aa:bb:cc", "aa:*:cc:dd:ee:ff", "00:11:22:33:44:02", "00:11:22:33:44:01"), true, true},
+		{"disjoint", newTestMatcher(t, "66:77:88:99:aa:bb", "dd:ee:ff"), false, false},
+		{"empty", NewMatcher(), true, false},
+		{"other pattern", newTestMatcher(t, "00:11:22:33:44:01", "aa:bb:*:dd:ee:ff"), false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.m.IsSubsetOf(known); got != tt.wantSub {
+			t.Errorf("%s: IsSubsetOf() = %v, want %v", tt.name, got, tt.wantSub)
+		}
+		if got := tt.m.IsSupersetOf(known); got != tt.wantSuper {
+			t.Errorf("%s: IsSupersetOf() = %v, want %v", tt.name, got, tt.wantSuper)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package macaddr
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Dump writes the entries of the matcher to w in the text format read by
+// ParseTextMacFile, one entry per line in lowercase colon notation.
+// EUI-48 addresses come first, followed by EUI-64 addresses, IPoIB
+// addresses of a NewMatcherIB matcher, prefixes, wildcard patterns and
+// ranges, each group sorted so the output is stable.
+// Tags are written as trailing comments.
+func (m *Matcher) Dump(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, k := range m.Entries() {
+		bw.WriteString(FormatMAC(k))
+		if tag := m.meta[k].tag; len(tag) > 0 {
+			bw.WriteString(" # ")
+			bw.WriteString(tag)
+		}
+		bw.WriteByte('\n')
+	}
+
+	for _, p := range m.patterns() {
+		bw.WriteString(p)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// patterns returns the entries of the matcher other than EUI-48 addresses
+// as patterns accepted by Add, in the order described by Dump.
+func (m *Matcher) patterns() []string {
+	var res, group []string
+	for k := range m.macs64 {
+		group = append(group, FormatHardwareAddr(k[:]))
+	}
+	slices.Sort(group)
+	res = append(res, group...)
+
+	group = group[:0]
+	for k := range m.macs20 {
+		group = append(group, FormatHardwareAddr(k[:]))
+	}
+	slices.Sort(group)
+	res = append(res, group...)
+
+	group = group[:0]
+	for k := range m.prefixes {
+		group = append(group, FormatHardwareAddr(k[:]))
+	}
+	slices.Sort(group)
+	res = append(res, group...)
+
+	masked := slices.Clone(m.masked)
+	slices.SortFunc(masked, func(a, b maskedMAC) int {
+		if c := bytes.Compare(a.value[:], b.value[:]); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.mask[:], b.mask[:])
+	})
+	for _, p := range masked {
+		res = append(res, p.String())
+	}
+
+	// Ranges are already sorted by lo.
+	for _, r := range m.ranges.ranges {
+		res = append(res, r.String())
+	}
+	return res
+}
+
+// String returns p in colon notation with "*" for wildcard octets.
+func (p maskedMAC) String() string {
+	octets := make([]string, len(p.value))
+	for i := range p.value {
+		if p.mask[i] == 0 {
+			octets[i] = "*"
+		} else {
+			octets[i] = fmt.Sprintf("%02x", p.value[i])
+		}
+	}
+	return strings.Join(octets, ":")
+}
+
+// dumper is implemented by matchers that can dump their entries.
+type dumper interface {
+	Dump(w io.Writer) error
+}
+
+// Dump writes the entries of the currently loaded matcher to w.
+// See Matcher.Dump. It returns an error if the loaded matcher cannot be
+// dumped. Nothing is written if no data has been loaded yet.
+func (d *DynamicMatcher) Dump(w io.Writer) error {
+	d.l.RLock()
+	defer d.l.RUnlock()
+	if d.m == nil {
+		return nil
+	}
+	dm, ok := d.m.(dumper)
+	if !ok {
+		return fmt.Errorf("matcher %T does not support Dump", d.m)
+	}
+	return dm.Dump(w)
+}
@@ -0,0 +1,65 @@
+package macaddr
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatcher_Dump(t *testing.T) {
+	m := NewMatcher()
+	for _, s := range []string{
+		"AA:BB:CC:DD:EE:FF",
+		"00-11-22-33-44-55",
+		"00:11:22:33:44:55:66:77",
+		"ac:de:48",
+		"aa:bb:*:dd:*:ff",
+		"00:11:22:33:44:00-00:11:22:33:44:0f",
+	} {
+		if err := m.Add(s, struct{}{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.AddWithTag("66:77:88:99:aa:bb", "living-room-tv"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := m.Dump(buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `00:11:22:33:44:55
+66:77:88:99:aa:bb # living-room-tv
+aa:bb:cc:dd:ee:ff
+00:11:22:33:44:55:66:77
+ac:de:48
+aa:bb:*:dd:*:ff
+00:11:22:33:44:00-00:11:22:33:44:0f
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Dump() =\n%s\nwant\n%s", got, want)
+	}
+
+	parsed, err := ParseTextMacFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, m) {
+		t.Errorf("dump does not round-trip, got %+v, want %+v", parsed, m)
+	}
+
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	if err := d.Update([]byte(want)); err != nil {
+		t.Fatal(err)
+	}
+	var dbuf strings.Builder
+	if err := d.Dump(&dbuf); err != nil {
+		t.Fatal(err)
+	}
+	if dbuf.String() != want {
+		t.Errorf("DynamicMatcher.Dump() =\n%s\nwant\n%s", dbuf.String(), want)
+	}
+}
@@ -0,0 +1,108 @@
+package macaddr
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// NamedMatcher is a LocalMatcher with a name for reports.
+type NamedMatcher struct {
+	Name    string
+	Matcher LocalMatcher
+}
+
+// Outcomes of an EvaluateResult.
+const (
+	EvaluateMatch   = "match"
+	EvaluateNoMatch = "no_match"
+	EvaluateError   = "error"
+)
+
+// EvaluateResult is the report of one input line of Evaluate.
+type EvaluateResult struct {
+	Line    int      `json:"line"`
+	Input   string   `json:"input"`
+	Outcome string   `json:"outcome"`
+	Matched []string `json:"matched,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// EvaluateAll returns the names of all of ms that match mac, in order.
+// Unlike LocalMatcherGroup.MatchDetailed, it does not stop at the first
+// hit, so a report shows every list a device is in.
+func EvaluateAll(mac net.HardwareAddr, ms []NamedMatcher) []string {
+	var names []string
+	for _, m := range ms {
+		if m.Matcher.Match(mac) {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// Evaluate reads one MAC per line from r and reports which of ms match it,
+// see EvaluateAll. Each line is parsed like a single MAC entry, e.g. the
+// bare form "aabbccddeeff" is accepted. Blank lines and lines starting
+// with "#" are skipped. A malformed line does not stop the evaluation,
+// it is reported with EvaluateError.
+// The returned error is only for read errors of r.
+func Evaluate(r io.Reader, ms []NamedMatcher) ([]EvaluateResult, error) {
+	var res []EvaluateResult
+	lineCounter := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineCounter++
+		s := strings.TrimSpace(scanner.Text())
+		if len(s) == 0 || strings.HasPrefix(s, "#") {
+			continue
+		}
+		er := EvaluateResult{Line: lineCounter, Input: s}
+		mac, err := parseMAC(s)
+		if err != nil {
+			er.Outcome = EvaluateError
+			er.Error = err.Error()
+			res = append(res, er)
+			continue
+		}
+		er.Matched = EvaluateAll(mac, ms)
+		if len(er.Matched) > 0 {
+			er.Outcome = EvaluateMatch
+		} else {
+			er.Outcome = EvaluateNoMatch
+		}
+		res = append(res, er)
+	}
+	return res, scanner.Err()
+}
+
+// WriteEvaluateCSV writes res as CSV with a header row. Matched matcher
+// names are joined by "|".
+func WriteEvaluateCSV(w io.Writer, res []EvaluateResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"line", "input", "outcome", "matched", "error"}); err != nil {
+		return err
+	}
+	for _, r := range res {
+		record := []string{strconv.Itoa(r.Line), r.Input, r.Outcome, strings.Join(r.Matched, "|"), r.Error}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteEvaluateJSON writes res as a JSON array.
+func WriteEvaluateJSON(w io.Writer, res []EvaluateResult) error {
+	if res == nil {
+		res = []EvaluateResult{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
@@ -0,0 +1,71 @@
+package macaddr
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	allow := newTestMatcher(t, "00:11:22:33:44:55", "aa:bb:cc:dd:ee:ff")
+	deny := newTestMatcher(t, "aa:bb:cc:dd:ee:ff")
+	ms := []NamedMatcher{{"allow", allow}, {"deny", deny}}
+
+	const input = `
+# devices
+00:11:22:33:44:55
+AA-BB-CC-DD-EE-FF
+not-a-mac
+66:77:88:99:aa:bb
+aabbccddeeff
+`
+	res, err := Evaluate(strings.NewReader(input), ms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []EvaluateResult{
+		{Line: 3, Input: "00:11:22:33:44:55", Outcome: EvaluateMatch, Matched: []string{"allow"}},
+		{Line: 4, Input: "AA-BB-CC-DD-EE-FF", Outcome: EvaluateMatch, Matched: []string{"allow", "deny"}},
+		{Line: 5, Input: "not-a-mac", Outcome: EvaluateError, Error: "address not-a-mac: invalid MAC address"},
+		{Line: 6, Input: "66:77:88:99:aa:bb", Outcome: EvaluateNoMatch},
+		{Line: 7, Input: "aabbccddeeff", Outcome: EvaluateMatch, Matched: []string{"allow", "deny"}},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("Evaluate() = %+v, want %+v", res, want)
+	}
+
+	b := new(bytes.Buffer)
+	if err := WriteEvaluateCSV(b, res); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 6 || lines[2] != "4,AA-BB-CC-DD-EE-FF,match,allow|deny," {
+		t.Errorf("unexpected csv output:\n%s", b)
+	}
+
+	b.Reset()
+	if err := WriteEvaluateJSON(b, res); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []EvaluateResult
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("json round trip = %+v, want %+v", decoded, want)
+	}
+}
+
+func TestEvaluateAll(t *testing.T) {
+	a := newTestMatcher(t, "00:11:22:33:44:55")
+	b := newTestMatcher(t, "00:11:22")
+	ms := []NamedMatcher{{"a", a}, {"b", b}}
+	if got := EvaluateAll(mustParseMAC("00:11:22:33:44:55"), ms); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("EvaluateAll() = %v, want [a b]", got)
+	}
+	if got := EvaluateAll(mustParseMAC("66:77:88:99:aa:bb"), ms); got != nil {
+		t.Errorf("EvaluateAll() = %v, want none", got)
+	}
+}
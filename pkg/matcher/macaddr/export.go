@@ -0,0 +1,59 @@
+package macaddr
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"slices"
+)
+
+// ExportFunc receives a batch of entries in a streaming export. cursor is
+// the last entry of the batch and can be passed to Export to resume after
+// it. The batch slice is reused between calls, so fn must not retain it.
+// Returning an error stops the export.
+type ExportFunc func(batch []net.HardwareAddr, cursor net.HardwareAddr) error
+
+// Export streams the entries after cursor to fn in ascending order, in
+// batches of at most batchSize entries. A nil cursor starts from the first
+// entry. Only the 6-byte keys are copied for sorting, the batches passed
+// to fn are never built for the whole list at once.
+// It returns the cursor of the last batch that fn accepted.
+func (m *Matcher) Export(cursor net.HardwareAddr, batchSize int, fn ExportFunc) (net.HardwareAddr, error) {
+	if batchSize <= 0 {
+		return cursor, fmt.Errorf("invalid batch size %d", batchSize)
+	}
+	var after [6]byte
+	if cursor != nil {
+		if len(cursor) != 6 {
+			return cursor, fmt.Errorf("invalid cursor %s", cursor)
+		}
+		after = [6]byte(cursor)
+	}
+
+	keys := make([][6]byte, 0, len(m.macs))
+	for k := range m.macs {
+		if cursor != nil && bytes.Compare(k[:], after[:]) <= 0 {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b [6]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	batch := make([]net.HardwareAddr, 0, min(batchSize, len(keys)))
+	for len(keys) > 0 {
+		n := min(batchSize, len(keys))
+		batch = batch[:0]
+		for i := range keys[:n] {
+			batch = append(batch, keys[i][:])
+		}
+		next := slices.Clone(batch[n-1])
+		if err := fn(batch, next); err != nil {
+			return cursor, err
+		}
+		cursor = next
+		keys = keys[n:]
+	}
+	return cursor, nil
+}
@@ -0,0 +1,66 @@
+package macaddr
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestMatcher_Export(t *testing.T) {
+	m := newTestMatcher(t,
+		"00:00:00:00:00:05",
+		"00:00:00:00:00:01",
+		"00:00:00:00:00:04",
+		"00:00:00:00:00:02",
+		"00:00:00:00:00:03",
+	)
+
+	var got []string
+	var batches int
+	cursor, err := m.Export(nil, 2, func(batch []net.HardwareAddr, _ net.HardwareAddr) error {
+		batches++
+		for _, mac := range batch {
+			got = append(got, mac.String())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches != 3 || len(got) != 5 || got[0] != "00:00:00:00:00:01" || got[4] != "00:00:00:00:00:05" {
+		t.Fatalf("unexpected export: %d batches, %v", batches, got)
+	}
+	if cursor.String() != "00:00:00:00:00:05" {
+		t.Errorf("final cursor = %s", cursor)
+	}
+
+	// Fail the second batch, then resume from the returned cursor.
+	errSink := errors.New("sink down")
+	calls := 0
+	cursor, err = m.Export(nil, 2, func(_ []net.HardwareAddr, _ net.HardwareAddr) error {
+		calls++
+		if calls == 2 {
+			return errSink
+		}
+		return nil
+	})
+	if !errors.Is(err, errSink) {
+		t.Fatalf("Export() err = %v, want %v", err, errSink)
+	}
+	if cursor.String() != "00:00:00:00:00:02" {
+		t.Fatalf("cursor after failure = %s, want 00:00:00:00:00:02", cursor)
+	}
+
+	got = got[:0]
+	if _, err := m.Export(cursor, 10, func(batch []net.HardwareAddr, _ net.HardwareAddr) error {
+		for _, mac := range batch {
+			got = append(got, mac.String())
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != "00:00:00:00:00:03" {
+		t.Errorf("resumed export = %v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package macaddr
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewFileWatchMatcher creates a DynamicMatcher loaded from the file at path,
+// and reloads it whenever the modification time of the file changes. The
+// file is polled every interval, so it does not need a data_provider.
+// The file may be in the text or the binary format.
+// If the file is missing or unreadable during a poll, e.g. while it is
+// being replaced by an atomic rename, the last good data is kept.
+// The returned function stops the polling and is safe to call more than once.
+func NewFileWatchMatcher(path string, interval time.Duration) (*DynamicMatcher, func(), error) {
+	if interval <= 0 {
+		return nil, nil, fmt.Errorf("invalid interval %s", interval)
+	}
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return parseMacFile(b, nil, 0)
+	})
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := d.Update(b); err != nil {
+		return nil, nil, fmt.Errorf("failed to load %s, %w", path, err)
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() { once.Do(func() { close(done) }) }
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		mtime := fi.ModTime()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			fi, err := os.Stat(path)
+			if err != nil || fi.ModTime().Equal(mtime) {
+				continue
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				continue // Retry on the next poll.
+			}
+			// A file with bad data is not retried until it changes again.
+			// The previous data is kept, see DynamicMatcher.Update.
+			mtime = fi.ModTime()
+			_ = d.Update(b)
+		}
+	}()
+	return d, stop, nil
+}
@@ -0,0 +1,58 @@
+package macaddr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewFileWatchMatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macs.txt")
+	if err := os.WriteFile(path, []byte("00:11:22:33:44:55\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	const interval = 10 * time.Millisecond
+	d, stop, err := NewFileWatchMatcher(path, interval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if !d.Match(mustParseMAC("00:11:22:33:44:55")) {
+		t.Fatal("initial data should be loaded")
+	}
+
+	// A missing file keeps the last good data.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(3 * interval)
+	if !d.Match(mustParseMAC("00:11:22:33:44:55")) {
+		t.Fatal("data should be kept while the file is missing")
+	}
+
+	// Write to a temp file and rename it, like an atomic update does.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte("00:11:22:33:44:55\n00:11:22:33:44:66\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the mtime changes even on file systems with a coarse mtime.
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(tmp, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(100 * interval)
+	for !d.Match(mustParseMAC("00:11:22:33:44:66")) {
+		if time.Now().After(deadline) {
+			t.Fatal("new entry was not loaded")
+		}
+		time.Sleep(interval)
+	}
+	stop()
+	stop()
+}
@@ -0,0 +1,95 @@
+package macaddr
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+)
+
+const (
+	// maxHostnameExprLen bounds the size of a hostname regexp. Go regexps
+	// run in linear time, so bounding the expression bounds the match cost.
+	maxHostnameExprLen = 256
+	// maxHostnameLen is the max length of a DNS name.
+	maxHostnameLen = 253
+)
+
+// HostnameSource looks up the hostname of a device, e.g. from DHCP leases
+// or PTR records.
+type HostnameSource interface {
+	Hostname(mac net.HardwareAddr) (hostname string, ok bool)
+}
+
+// HostnameSourceFunc is a function that implements HostnameSource.
+type HostnameSourceFunc func(mac net.HardwareAddr) (string, bool)
+
+func (f HostnameSourceFunc) Hostname(mac net.HardwareAddr) (string, bool) {
+	return f(mac)
+}
+
+// OUIHostnameMatcher matches a device if its OUI is in the matcher AND
+// its hostname from HostnameSource matches the regexp.
+type OUIHostnameMatcher struct {
+	ouis map[[3]byte]struct{}
+	re   *regexp.Regexp
+	src  HostnameSource
+}
+
+// NewOUIHostnameMatcher creates a OUIHostnameMatcher. ouis are 3-octet
+// prefixes, e.g. "b8:27:eb". expr is compiled once here.
+func NewOUIHostnameMatcher(ouis []string, expr string, src HostnameSource) (*OUIHostnameMatcher, error) {
+	if src == nil {
+		return nil, fmt.Errorf("nil hostname source")
+	}
+	if len(expr) > maxHostnameExprLen {
+		return nil, fmt.Errorf("hostname regexp is too long, max %d, got %d", maxHostnameExprLen, len(expr))
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hostname regexp %s: %w", expr, err)
+	}
+
+	m := &OUIHostnameMatcher{
+		ouis: make(map[[3]byte]struct{}, len(ouis)),
+		re:   re,
+		src:  src,
+	}
+	for _, s := range ouis {
+		p, err := parsePartialMAC(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(p) != 3 {
+			return nil, fmt.Errorf("invalid OUI %s: expect 3 octets, got %d", s, len(p))
+		}
+		m.ouis[[3]byte(p)] = struct{}{}
+	}
+	return m, nil
+}
+
+func (m *OUIHostnameMatcher) Match(mac net.HardwareAddr) bool {
+	if len(mac) != 6 {
+		return false
+	}
+	if _, ok := m.ouis[[3]byte(mac)]; !ok {
+		return false
+	}
+	hostname, ok := m.src.Hostname(mac)
+	if !ok || len(hostname) > maxHostnameLen {
+		return false
+	}
+	return m.re.MatchString(hostname)
+}
+
+// Len returns the number of OUIs in the matcher.
+func (m *OUIHostnameMatcher) Len() int {
+	return len(m.ouis)
+}
+
+func (m *OUIHostnameMatcher) Close() error {
+	return nil
+}
+
+func (m *OUIHostnameMatcher) Kind() string { return "oui_hostname" }
+
+var _ LocalMatcher = (*OUIHostnameMatcher)(nil)
@@ -0,0 +1,54 @@
+package macaddr
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestOUIHostnameMatcher(t *testing.T) {
+	hostnames := map[string]string{
+		"b8:27:eb:00:00:01": "pi-kitchen",
+		"b8:27:eb:00:00:02": "octoprint",
+		"dc:a6:32:00:00:01": "pi-garage",
+		"00:11:22:33:44:55": "pi-fake",
+	}
+	src := HostnameSourceFunc(func(mac net.HardwareAddr) (string, bool) {
+		h, ok := hostnames[mac.String()]
+		return h, ok
+	})
+
+	m, err := NewOUIHostnameMatcher([]string{"b8:27:eb", "DC-A6-32"}, "^pi-", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+
+	tests := []struct {
+		mac  string
+		want bool
+	}{
+		{"b8:27:eb:00:00:01", true},  // OUI and hostname
+		{"dc:a6:32:00:00:01", true},  // second OUI
+		{"b8:27:eb:00:00:02", false}, // hostname mismatch
+		{"00:11:22:33:44:55", false}, // OUI mismatch
+		{"b8:27:eb:00:00:03", false}, // no hostname
+	}
+	for _, tt := range tests {
+		if got := m.Match(mustParseMAC(tt.mac)); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.mac, got, tt.want)
+		}
+	}
+
+	if _, err := NewOUIHostnameMatcher([]string{"b8:27"}, "^pi-", src); err == nil {
+		t.Error("2-octet OUI should fail")
+	}
+	if _, err := NewOUIHostnameMatcher(nil, "(", src); err == nil {
+		t.Error("invalid regexp should fail")
+	}
+	if _, err := NewOUIHostnameMatcher(nil, strings.Repeat("a", maxHostnameExprLen+1), src); err == nil {
+		t.Error("too long regexp should fail")
+	}
+}
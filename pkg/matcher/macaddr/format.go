@@ -0,0 +1,28 @@
+package macaddr
+
+import "net"
+
+// FormatMAC returns key in the canonical notation of the package, lowercase
+// hex octets separated by colons, e.g. "aa:bb:cc:dd:ee:ff". Dump and
+// MarshalJSON write entries in this notation.
+func FormatMAC(key [6]byte) string {
+	return FormatHardwareAddr(key[:])
+}
+
+// FormatHardwareAddr is like FormatMAC, for an address of any length, e.g.
+// an EUI-64 address or a 3-octet prefix. It formats like
+// net.HardwareAddr.String, which is lowercase.
+func FormatHardwareAddr(mac net.HardwareAddr) string {
+	if len(mac) == 0 {
+		return ""
+	}
+	const digits = "0123456789abcdef"
+	buf := make([]byte, 0, len(mac)*3-1)
+	for i, b := range mac {
+		if i > 0 {
+			buf = append(buf, ':')
+		}
+		buf = append(buf, digits[b>>4], digits[b&0x0f])
+	}
+	return string(buf)
+}
@@ -0,0 +1,41 @@
+package macaddr
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestFormatMAC(t *testing.T) {
+	tests := []string{
+		"AA:BB:CC:DD:EE:FF",
+		"00-11-22-33-44-55",
+		"0a1b.2c3d.4e5f",
+		"aa:bb:cc:dd:ee:ff:00:11",
+		"aa:bb:cc",
+	}
+	for _, s := range tests {
+		mac, err := parsePartialMAC(s)
+		if err != nil {
+			mac, err = net.ParseMAC(s)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := FormatHardwareAddr(mac)
+		if want := net.HardwareAddr(mac).String(); got != want {
+			t.Errorf("FormatHardwareAddr(%s) = %s, want %s", s, got, want)
+		}
+		if got != strings.ToLower(got) {
+			t.Errorf("FormatHardwareAddr(%s) = %s, want lowercase", s, got)
+		}
+	}
+
+	key := [6]byte{0xAA, 0xBB, 0xCC, 0x0D, 0x0E, 0x0F}
+	if got, want := FormatMAC(key), "aa:bb:cc:0d:0e:0f"; got != want {
+		t.Errorf("FormatMAC() = %s, want %s", got, want)
+	}
+	if got := FormatHardwareAddr(nil); got != "" {
+		t.Errorf("FormatHardwareAddr(nil) = %q, want empty", got)
+	}
+}
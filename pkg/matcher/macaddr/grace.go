@@ -0,0 +1,43 @@
+package macaddr
+
+import (
+	"net"
+)
+
+// Outcome is the result of a match that may hit a recently removed entry.
+type Outcome int
+
+const (
+	OutcomeMiss Outcome = iota
+	OutcomeHit
+	// OutcomeGrace means the entry was removed from the list but is still
+	// in its grace window.
+	OutcomeGrace
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeMiss:
+		return "miss"
+	case OutcomeHit:
+		return "hit"
+	case OutcomeGrace:
+		return "grace"
+	default:
+		return "unknown"
+	}
+}
+
+// matchOutcome returns the Outcome of m for mac. Matchers without grace
+// support can only hit or miss.
+func matchOutcome(m LocalMatcher, mac net.HardwareAddr) Outcome {
+	if om, ok := m.(interface {
+		MatchOutcome(mac net.HardwareAddr) Outcome
+	}); ok {
+		return om.MatchOutcome(mac)
+	}
+	if m.Match(mac) {
+		return OutcomeHit
+	}
+	return OutcomeMiss
+}
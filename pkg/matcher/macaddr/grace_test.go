@@ -0,0 +1,66 @@
+package macaddr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDynamicMatcher_GracePeriod(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	d.now = func() time.Time { return now }
+	d.SetGracePeriod(time.Minute)
+
+	kept := mustParseMAC("00:11:22:33:44:55")
+	removed := mustParseMAC("00:11:22:33:44:66")
+
+	if err := d.Update([]byte("00:11:22:33:44:55\n00:11:22:33:44:66\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Update([]byte("00:11:22:33:44:55\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if o := d.MatchOutcome(kept); o != OutcomeHit {
+		t.Errorf("kept entry outcome = %s, want hit", o)
+	}
+	now = now.Add(30 * time.Second)
+	if o := d.MatchOutcome(removed); o != OutcomeGrace {
+		t.Errorf("removed entry outcome = %s, want grace", o)
+	}
+	if !d.Match(removed) {
+		t.Error("removed entry should still match during grace")
+	}
+
+	mg := &LocalMatcherGroup{}
+	mg.Append(NewMatcher())
+	mg.Append(d)
+	if o := mg.MatchOutcome(removed); o != OutcomeGrace {
+		t.Errorf("group outcome = %s, want grace", o)
+	}
+
+	now = now.Add(time.Minute)
+	if o := d.MatchOutcome(removed); o != OutcomeMiss {
+		t.Errorf("removed entry outcome after grace = %s, want miss", o)
+	}
+	if d.Match(removed) {
+		t.Error("removed entry should not match after grace")
+	}
+}
+
+func TestDynamicMatcher_NoGracePeriod(t *testing.T) {
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	if err := d.Update([]byte("00:11:22:33:44:66\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Update(nil); err != nil {
+		t.Fatal(err)
+	}
+	if o := d.MatchOutcome(mustParseMAC("00:11:22:33:44:66")); o != OutcomeMiss {
+		t.Errorf("outcome = %s, want miss", o)
+	}
+}
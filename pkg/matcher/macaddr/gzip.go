@@ -0,0 +1,35 @@
+package macaddr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// isGzip reports whether in starts with the gzip magic bytes.
+func isGzip(in []byte) bool {
+	return len(in) >= 2 && in[0] == 0x1f && in[1] == 0x8b
+}
+
+// newGzipReader returns a reader of the decompressed content of in.
+func newGzipReader(in []byte) (io.Reader, error) {
+	r, err := gzip.NewReader(bytes.NewReader(in))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data, %w", err)
+	}
+	return r, nil
+}
+
+// gunzip decompresses in.
+func gunzip(in []byte) ([]byte, error) {
+	r, err := newGzipReader(in)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data, %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,60 @@
+package macaddr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseTextMacFile_Gzip(t *testing.T) {
+	plain := []byte("# list\n00:11:22:33:44:55\naa:bb:cc\naa:bb:cc:dd:ee:ff # tv\n")
+	want, err := ParseTextMacFile(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseTextMacFile(gzipBytes(t, plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gzip parse = %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseTextMacFile([]byte{0x1f, 0x8b, 0x00}); err == nil {
+		t.Error("expected error for corrupted gzip data")
+	}
+}
+
+func TestParseMacFile_Gzip(t *testing.T) {
+	want := newTestMatcher(t, "00:11:22:33:44:55", "aa:bb:cc:dd:ee:ff")
+	bin := new(bytes.Buffer)
+	if err := want.WriteBinary(bin); err != nil {
+		t.Fatal(err)
+	}
+	for name, in := range map[string][]byte{
+		"text":   gzipBytes(t, []byte("00:11:22:33:44:55\naa:bb:cc:dd:ee:ff\n")),
+		"binary": gzipBytes(t, bin.Bytes()),
+	} {
+		got, err := parseMacFile(in, nil, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got.Entries(), want.Entries()) {
+			t.Errorf("%s: entries = %v, want %v", name, got.Entries(), want.Entries())
+		}
+	}
+}
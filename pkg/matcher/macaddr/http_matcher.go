@@ -0,0 +1,108 @@
+package macaddr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// httpMatcherTimeout is the timeout of a single fetch of NewHTTPMatcher.
+const httpMatcherTimeout = 30 * time.Second
+
+// httpMatcherMaxSize limits the size of a list fetched by NewHTTPMatcher.
+const httpMatcherMaxSize = 64 << 20
+
+// NewHTTPMatcher creates a DynamicMatcher loaded from url, and refetches
+// it every refresh. The list may be in the text or the binary format.
+// Refetches are conditional on the ETag and Last-Modified of the last
+// response, and a 304 Not Modified keeps the current data without parsing.
+// If a refetch fails, the current data is kept.
+// The returned function stops the refresh and is safe to call more than once.
+func NewHTTPMatcher(url string, refresh time.Duration) (*DynamicMatcher, func(), error) {
+	if refresh <= 0 {
+		return nil, nil, fmt.Errorf("invalid refresh interval %s", refresh)
+	}
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return parseMacFile(b, nil, 0)
+	})
+	f := &httpFetcher{
+		url:    url,
+		client: &http.Client{Timeout: httpMatcherTimeout},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b, err := f.fetch(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	if err := d.Update(b); err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("failed to load %s, %w", url, err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(refresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			b, err := f.fetch(ctx)
+			if err != nil || b == nil {
+				continue
+			}
+			_ = d.Update(b)
+		}
+	}()
+	return d, cancel, nil
+}
+
+// httpFetcher fetches a url with conditional requests.
+type httpFetcher struct {
+	url          string
+	client       *http.Client
+	etag         string
+	lastModified string
+}
+
+// fetch returns the body of url, or nil if it was not modified since the
+// last fetch.
+func (f *httpFetcher) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.etag) > 0 {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+	if len(f.lastModified) > 0 {
+		req.Header.Set("If-Modified-Since", f.lastModified)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to fetch %s, status %s", f.url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, httpMatcherMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s, %w", f.url, err)
+	}
+	if len(b) > httpMatcherMaxSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", f.url, httpMatcherMaxSize)
+	}
+	f.etag = resp.Header.Get("ETag")
+	f.lastModified = resp.Header.Get("Last-Modified")
+	return b, nil
+}
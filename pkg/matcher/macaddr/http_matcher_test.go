@@ -0,0 +1,48 @@
+package macaddr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPMatcher(t *testing.T) {
+	var requests, notModified atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("00:11:22:33:44:55\n00:11:22:33:44:66\n"))
+	}))
+	defer srv.Close()
+
+	const refresh = 10 * time.Millisecond
+	d, stop, err := NewHTTPMatcher(srv.URL, refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	deadline := time.Now().Add(100 * refresh)
+	for notModified.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d requests, %d not modified, want conditional refetches", requests.Load(), notModified.Load())
+		}
+		time.Sleep(refresh)
+	}
+	if d.Len() != 2 || !d.Match(mustParseMAC("00:11:22:33:44:66")) {
+		t.Errorf("data should be kept on 304, Len() = %d", d.Len())
+	}
+
+	stop()
+	stop()
+	if _, _, err := NewHTTPMatcher(srv.URL+"/missing\x00", refresh); err == nil {
+		t.Error("expected error for an invalid url")
+	}
+}
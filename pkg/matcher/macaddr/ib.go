@@ -0,0 +1,40 @@
+package macaddr
+
+import "fmt"
+
+// NewMatcherIB creates a new empty Matcher that also accepts the 20-byte
+// IP over InfiniBand addresses parsed by net.ParseMAC, e.g.
+// "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01".
+// Other matchers reject them, so they do not pay for the extra map.
+func NewMatcherIB() *Matcher {
+	m := NewMatcher()
+	m.macs20 = make(map[[20]byte]struct{})
+	return m
+}
+
+// lenError returns the error for an address of n bytes, which m cannot
+// store. The wording is stable, so tools may rely on it.
+func (m *Matcher) lenError(n int) error {
+	if m.macs20 != nil {
+		return fmt.Errorf("expected 6-octet EUI-48, 8-octet EUI-64 or 20-octet IPoIB, got %d-octet address", n)
+	}
+	return fmt.Errorf("expected 6-octet EUI-48 or 8-octet EUI-64, got %d-octet address", n)
+}
+
+// eui48LenError returns the error for an address of n bytes where only
+// EUI-48 is supported, e.g. in ranges.
+func eui48LenError(n int) error {
+	return fmt.Errorf("expected 6-octet EUI-48, got %d-octet address", n)
+}
+
+// validLen reports whether m can store an address of n bytes.
+func (m *Matcher) validLen(n int) bool {
+	switch n {
+	case 6, 8:
+		return true
+	case 20:
+		return m.macs20 != nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package macaddr
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestNewMatcherIB(t *testing.T) {
+	const ib = "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"
+	const other = "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:02"
+
+	m := NewMatcherIB()
+	if err := m.AddMany(ib, "00:11:22:33:44:55"); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Match(mustParseMAC(ib)) || m.Match(mustParseMAC(other)) {
+		t.Error("20-byte address should round-trip through Add and Match")
+	}
+	if !m.Match(mustParseMAC("00:11:22:33:44:55")) || m.Len() != 2 {
+		t.Error("IB matcher should still match EUI-48 addresses")
+	}
+	if err := m.Remove(ib); err != nil || m.Match(mustParseMAC(ib)) {
+		t.Errorf("Remove() = %v, address should be removed", err)
+	}
+
+	if err := NewMatcher().Add(ib, struct{}{}); err == nil {
+		t.Error("default matcher should reject 20-byte addresses")
+	}
+	if NewMatcher().Match(mustParseMAC(ib)) {
+		t.Error("default matcher should not match 20-byte addresses")
+	}
+}
+
+func TestMatcherIB_RoundTrip(t *testing.T) {
+	const ib = "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"
+	m := NewMatcherIB()
+	if err := m.AddMany(ib, "00:11:22:33:44:55"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := m.Dump(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "00:11:22:33:44:55\n" + ib + "\n"
+	if buf.String() != want {
+		t.Errorf("Dump() = %q, want %q", buf.String(), want)
+	}
+	dumped := NewMatcherIB()
+	if err := LoadFromTextReader(dumped, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if dumped.Len() != m.Len() || !dumped.Match(mustParseMAC(ib)) {
+		t.Errorf("Dump round trip Len() = %d, want %d with the IPoIB address", dumped.Len(), m.Len())
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := NewMatcherIB()
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Len() != m.Len() || !decoded.Match(mustParseMAC(ib)) {
+		t.Errorf("JSON round trip of %s has Len() %d, want %d with the IPoIB address", b, decoded.Len(), m.Len())
+	}
+}
@@ -0,0 +1,69 @@
+package macaddr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// LoadFromTextReaderDir is like LoadFromTextReader, but also loads the
+// files named by "@include path" lines, e.g. "@include phones.txt".
+// A relative path is resolved against baseDir, the directory of the file
+// being read, and paths in included files against their own directories.
+// Includes may nest, but a cyclic include is an error.
+func LoadFromTextReaderDir(m LocalWriteableMatcher, r io.Reader, baseDir string) error {
+	return loadFromTextReader(m, r, nil, defaultCommentPrefixes, nil, &includer{dir: baseDir}, 0)
+}
+
+// includer loads the files included by a text mac file.
+type includer struct {
+	dir   string   // directory to resolve relative paths against
+	stack []string // absolute paths of the files being loaded
+}
+
+// cutInclude returns the path of an "@include path" line.
+func cutInclude(s string) (string, bool) {
+	rest, ok := strings.CutPrefix(s, "@include")
+	if !ok || (len(rest) > 0 && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// load loads the file at path into m.
+func (inc *includer) load(
+	m LocalWriteableMatcher,
+	path string,
+	sf siteFilter,
+	commentPrefixes []string,
+	skip func(line int, err error),
+) error {
+	if len(path) == 0 {
+		return errors.New("@include needs a path")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(inc.dir, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid include path %s, %w", path, err)
+	}
+	if slices.Contains(inc.stack, abs) {
+		return fmt.Errorf("cyclic include of %s", abs)
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return fmt.Errorf("failed to open included file, %w", err)
+	}
+	defer f.Close()
+
+	next := &includer{dir: filepath.Dir(abs), stack: append(slices.Clip(inc.stack), abs)}
+	if err := loadFromTextReader(m, f, sf, commentPrefixes, skip, next, 0); err != nil {
+		return fmt.Errorf("include %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package macaddr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFromTextReaderDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "lists", "b.txt"), "00:11:22:33:44:66\n@include c.txt\n")
+	writeTestFile(t, filepath.Join(dir, "lists", "c.txt"), "00:11:22:33:44:77\n")
+	const a = `
+00:11:22:33:44:55
+@include lists/b.txt # phones
+`
+	m := NewMatcher()
+	if err := LoadFromTextReaderDir(m, strings.NewReader(a), dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, mac := range []string{"00:11:22:33:44:55", "00:11:22:33:44:66", "00:11:22:33:44:77"} {
+		if !m.Match(mustParseMAC(mac)) {
+			t.Errorf("Match(%s) = false, want true", mac)
+		}
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+
+	if err := LoadFromTextReader(NewMatcher(), strings.NewReader(a)); err == nil {
+		t.Error("LoadFromTextReader() should not accept @include lines")
+	}
+}
+
+func TestLoadFromTextReaderDir_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.txt"), "@include b.txt\n")
+	writeTestFile(t, filepath.Join(dir, "b.txt"), "@include a.txt\n")
+	writeTestFile(t, filepath.Join(dir, "self.txt"), "@include ./self.txt\n")
+	tests := []string{
+		"@include a.txt\n",
+		"@include self.txt\n",
+		"@include missing.txt\n",
+		"@include\n",
+	}
+	for _, data := range tests {
+		if err := LoadFromTextReaderDir(NewMatcher(), strings.NewReader(data), dir); err == nil {
+			t.Errorf("LoadFromTextReaderDir(%q) should fail", data)
+		}
+	}
+	err := LoadFromTextReaderDir(NewMatcher(), strings.NewReader("@include a.txt\n"), dir)
+	if err == nil || !strings.Contains(err.Error(), "cyclic include") {
+		t.Errorf("error = %v, want a cyclic include error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package macaddr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonMatcher is the JSON form of a Matcher.
+type jsonMatcher struct {
+	Macs []string `json:"macs"`
+}
+
+// MarshalJSON encodes the entries of the matcher as
+// {"macs":["aa:bb:cc:dd:ee:ff", ...]}, in the lowercase notation and the
+// order of Dump. Tags are not encoded.
+func (m *Matcher) MarshalJSON() ([]byte, error) {
+	entries := m.Entries()
+	jm := jsonMatcher{Macs: make([]string, 0, m.Len())}
+	for _, k := range entries {
+		jm.Macs = append(jm.Macs, FormatMAC(k))
+	}
+	jm.Macs = append(jm.Macs, m.patterns()...)
+	return json.Marshal(jm)
+}
+
+// UnmarshalJSON replaces the entries of the matcher with the ones encoded
+// by MarshalJSON. Each entry may be any pattern accepted by Add, and
+// duplicates are merged. On error, the matcher is left unchanged.
+// Options of the matcher, e.g. strict mode, are kept.
+func (m *Matcher) UnmarshalJSON(b []byte) error {
+	var jm jsonMatcher
+	if err := json.Unmarshal(b, &jm); err != nil {
+		return err
+	}
+	n := NewMatcherSize(len(jm.Macs))
+	n.strict = m.strict
+	n.observer = m.observer
+	if m.macs20 != nil {
+		n.macs20 = make(map[[20]byte]struct{})
+	}
+	if m.bloom != nil {
+		n.bloom = newBloomFilter(len(jm.Macs))
+	}
+	if err := n.AddMany(jm.Macs...); err != nil {
+		return fmt.Errorf("invalid macs, %w", err)
+	}
+	*m = *n
+	return nil
+}
+
+// ParseJSONMacFile parses MAC addresses from JSON bytes in the format of
+// Matcher.MarshalJSON.
+func ParseJSONMacFile(in []byte) (*Matcher, error) {
+	m := NewMatcher()
+	if err := json.Unmarshal(in, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// isJSONMacFile reports whether in looks like a JSON object. A text mac
+// file never starts with '{'.
+func isJSONMacFile(in []byte) bool {
+	in = bytes.TrimLeft(in, " \t\r\n")
+	return len(in) > 0 && in[0] == '{'
+}
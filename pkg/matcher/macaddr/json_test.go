@@ -0,0 +1,52 @@
+package macaddr
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMatcher_JSON(t *testing.T) {
+	m := newTestMatcher(t, "AA:BB:CC:DD:EE:FF", "00-11-22-33-44-55", "ac:de:48", "00:11:22:33:44:00-00:11:22:33:44:0f")
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"macs":["00:11:22:33:44:55","aa:bb:cc:dd:ee:ff","ac:de:48","00:11:22:33:44:00-00:11:22:33:44:0f"]}`
+	if string(b) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", b, want)
+	}
+
+	got := NewMatcher()
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("JSON does not round-trip, got %+v, want %+v", got, m)
+	}
+
+	dup, err := ParseJSONMacFile([]byte(`{"macs":["aa:bb:cc:dd:ee:ff","AA-BB-CC-DD-EE-FF"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dup.Len() != 1 {
+		t.Errorf("duplicates should be merged, Len() = %d", dup.Len())
+	}
+
+	m2, err := parseMacFile([]byte("\n"+want), nil, 0)
+	if err != nil || m2.Len() != 4 {
+		t.Errorf("parseMacFile() should detect JSON, got %v, %v", m2, err)
+	}
+}
+
+func TestMatcher_UnmarshalJSONError(t *testing.T) {
+	for _, in := range []string{`{"macs":`, `{"macs":"aa:bb:cc:dd:ee:ff"}`, `{"macs":["bad"]}`} {
+		m := newTestMatcher(t, "00:11:22:33:44:55")
+		if err := json.Unmarshal([]byte(in), m); err == nil {
+			t.Errorf("Unmarshal(%s) should fail", in)
+		}
+		if m.Len() != 1 {
+			t.Errorf("Unmarshal(%s) failure should keep the matcher, Len() = %d", in, m.Len())
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package macaddr
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+)
+
+// ParseDHCPLeaseFile parses the MAC addresses of a dnsmasq lease file.
+// Each lease line is "expiry mac ip hostname clientid". The hostname, if
+// known, becomes the tag of the entry, see Matcher.MatchWithTag.
+// The "duid" line and malformed lines are skipped. It returns an error if
+// the file has no valid lease.
+func ParseDHCPLeaseFile(in []byte) (*Matcher, error) {
+	m := NewMatcher()
+	leases := 0
+	scanner := bufio.NewScanner(bytes.NewReader(in))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[0] == "duid" {
+			continue
+		}
+		mac, err := net.ParseMAC(fields[1])
+		if err != nil || len(mac) != 6 {
+			continue
+		}
+		hostname := fields[3]
+		if hostname == "*" {
+			hostname = ""
+		}
+		if err := m.AddWithTag(mac.String(), hostname); err != nil {
+			continue
+		}
+		leases++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if leases == 0 {
+		return nil, errors.New("no valid lease found")
+	}
+	return m, nil
+}
@@ -0,0 +1,39 @@
+package macaddr
+
+import "testing"
+
+const dnsmasqLeases = `duid 00:01:00:01:2c:5e:8f:3a:aa:bb:cc:dd:ee:ff
+1697040000 aa:bb:cc:dd:ee:ff 192.168.1.10 laptop 01:aa:bb:cc:dd:ee:ff
+1697043600 00:11:22:33:44:55 192.168.1.11 * 01:00:11:22:33:44:55
+1697047200 66:77:88:99:aa:bb 192.168.1.12 living-room-tv *
+1697047200 not-a-mac 192.168.1.13 broken *
+truncated line
+`
+
+func TestParseDHCPLeaseFile(t *testing.T) {
+	m, err := ParseDHCPLeaseFile([]byte(dnsmasqLeases))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+	tests := []struct {
+		mac string
+		tag string
+	}{
+		{"aa:bb:cc:dd:ee:ff", "laptop"},
+		{"00:11:22:33:44:55", ""},
+		{"66:77:88:99:aa:bb", "living-room-tv"},
+	}
+	for _, tt := range tests {
+		tag, ok := m.MatchWithTag(mustParseMAC(tt.mac))
+		if !ok || tag != tt.tag {
+			t.Errorf("MatchWithTag(%s) = %q, %v, want %q, true", tt.mac, tag, ok, tt.tag)
+		}
+	}
+
+	if _, err := ParseDHCPLeaseFile([]byte("duid 00:01\nbroken\n")); err == nil {
+		t.Error("expected error for a file without valid leases")
+	}
+}
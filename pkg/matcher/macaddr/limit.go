@@ -0,0 +1,62 @@
+package macaddr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pmkol/mosdns-x/pkg/data_provider"
+)
+
+// ErrTooManyEntries is returned if data has more entries than the limit,
+// see ParseTextMacFileLimited.
+var ErrTooManyEntries = errors.New("too many entries")
+
+// ParseTextMacFileLimited is like ParseTextMacFile, but stops and returns
+// ErrTooManyEntries once the data has more than maxEntries entries, e.g.
+// to refuse an absurd update of a DynamicMatcher. 0 means unlimited.
+func ParseTextMacFileLimited(in []byte, maxEntries int) (*Matcher, error) {
+	return parseTextMacFile(in, nil, maxEntries, nil)
+}
+
+// BatchLoadMacProviderLimited is like BatchLoadMacProviderSites, but each
+// provider or file source fails with ErrTooManyEntries if its data has
+// more than maxEntries entries. A provider that fails on reload keeps its
+// previous data. 0 means unlimited.
+func BatchLoadMacProviderLimited(
+	e []string,
+	dm *data_provider.DataManager,
+	sites []string,
+	maxEntries int,
+) (*LocalMatcherGroup, error) {
+	return batchLoadMacProvider(e, dm, newSiteFilter(sites), maxEntries, NewMatcher())
+}
+
+// checkMaxEntries returns an error if m has more than maxEntries entries.
+// 0 disables the check.
+func checkMaxEntries(m LocalMatcher, maxEntries int) error {
+	if maxEntries > 0 && m.Len() > maxEntries {
+		return fmt.Errorf("%w, the limit is %d", ErrTooManyEntries, maxEntries)
+	}
+	return nil
+}
+
+// limitedMatcher is a Matcher whose additions fail once it has more than
+// max entries.
+type limitedMatcher struct {
+	*Matcher
+	max int
+}
+
+func (l limitedMatcher) Add(pattern string, v struct{}) error {
+	if err := l.Matcher.Add(pattern, v); err != nil {
+		return err
+	}
+	return checkMaxEntries(l.Matcher, l.max)
+}
+
+func (l limitedMatcher) AddWithTag(pattern, tag string) error {
+	if err := l.Matcher.AddWithTag(pattern, tag); err != nil {
+		return err
+	}
+	return checkMaxEntries(l.Matcher, l.max)
+}
@@ -0,0 +1,42 @@
+package macaddr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTextMacFileLimited(t *testing.T) {
+	const data = "00:11:22:33:44:01\n00:11:22:33:44:02 # tv\n00:11:22:33:44:02\naa:bb:cc\n"
+	m, err := ParseTextMacFileLimited([]byte(data), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+	if _, err := ParseTextMacFileLimited([]byte(data), 2); !errors.Is(err, ErrTooManyEntries) {
+		t.Errorf("error = %v, want ErrTooManyEntries", err)
+	}
+	if m, err := ParseTextMacFileLimited([]byte(data), 0); err != nil || m.Len() != 3 {
+		t.Errorf("unlimited parse = %v, %v, want 3 entries", m, err)
+	}
+}
+
+func TestBatchLoadMacProviderLimited(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macs.json")
+	if err := os.WriteFile(path, []byte(`{"macs":["00:11:22:33:44:01","00:11:22:33:44:02"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BatchLoadMacProviderLimited([]string{"file:" + path}, nil, nil, 1); !errors.Is(err, ErrTooManyEntries) {
+		t.Errorf("error = %v, want ErrTooManyEntries", err)
+	}
+	mg, err := BatchLoadMacProviderLimited([]string{"file:" + path}, nil, nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mg.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", mg.Len())
+	}
+}
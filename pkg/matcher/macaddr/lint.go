@@ -0,0 +1,76 @@
+package macaddr
+
+import "fmt"
+
+// LintCode classifies a Warning of Matcher.Lint.
+type LintCode int
+
+const (
+	// LintOverlappingRanges is reported for two ranges that overlap.
+	LintOverlappingRanges LintCode = iota + 1
+	// LintRangeCoversExact is reported for an exact entry within a range.
+	LintRangeCoversExact
+	// LintPrefixCoversExact is reported for an exact entry whose OUI is a
+	// listed prefix.
+	LintPrefixCoversExact
+)
+
+// Warning is a finding of Matcher.Lint.
+type Warning struct {
+	Code    LintCode
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
+
+// Lint reports entries of the matcher that are redundant or likely
+// mistakes, but not invalid: overlapping ranges, and exact EUI-48 entries
+// that a range or a prefix already matches. It is a diagnostic to run on
+// demand, e.g. in a CI check of a list, and it scans the whole matcher.
+// Warnings are ordered by code, then by entry.
+func (m *Matcher) Lint() []Warning {
+	var ws []Warning
+
+	// Ranges are sorted by lo, so a range overlaps a previous one iff its
+	// lo is not above the highest hi so far.
+	ranges := m.ranges.ranges
+	for i, widest := 1, 0; i < len(ranges); i++ {
+		if ranges[i].lo <= ranges[widest].hi {
+			ws = append(ws, Warning{
+				Code:    LintOverlappingRanges,
+				Message: fmt.Sprintf("range %s overlaps range %s", ranges[i], ranges[widest]),
+			})
+		}
+		if ranges[i].hi > ranges[widest].hi {
+			widest = i
+		}
+	}
+
+	entries := m.Entries()
+	for _, k := range entries {
+		v := mac48ToUint64(k[:])
+		if !m.ranges.contains(v) {
+			continue
+		}
+		for _, r := range ranges {
+			if r.lo <= v && v <= r.hi {
+				ws = append(ws, Warning{
+					Code:    LintRangeCoversExact,
+					Message: fmt.Sprintf("%s is already covered by range %s", FormatMAC(k), r),
+				})
+				break
+			}
+		}
+	}
+	for _, k := range entries {
+		if _, ok := m.prefixes[[3]byte(k[:3])]; ok {
+			ws = append(ws, Warning{
+				Code:    LintPrefixCoversExact,
+				Message: fmt.Sprintf("%s is already covered by prefix %s", FormatMAC(k), FormatHardwareAddr(k[:3])),
+			})
+		}
+	}
+	return ws
+}
@@ -0,0 +1,30 @@
+package macaddr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcher_Lint(t *testing.T) {
+	m := newTestMatcher(t,
+		"00:11:22:33:44:00-00:11:22:33:44:ff",
+		"00:11:22:33:44:f0-00:11:22:33:45:0f",
+		"00:11:22:33:46:00-00:11:22:33:46:ff",
+		"00:11:22:33:44:10",
+		"aa:bb:cc",
+		"aa:bb:cc:dd:ee:ff",
+		"66:77:88:99:aa:bb",
+	)
+	want := []Warning{
+		{LintOverlappingRanges, "range 00:11:22:33:44:f0-00:11:22:33:45:0f overlaps range 00:11:22:33:44:00-00:11:22:33:44:ff"},
+		{LintRangeCoversExact, "00:11:22:33:44:10 is already covered by range 00:11:22:33:44:00-00:11:22:33:44:ff"},
+		{LintPrefixCoversExact, "aa:bb:cc:dd:ee:ff is already covered by prefix aa:bb:cc"},
+	}
+	if got := m.Lint(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lint() = %v, want %v", got, want)
+	}
+
+	if got := newTestMatcher(t, "00:11:22:33:44:55", "aa:bb:cc").Lint(); len(got) != 0 {
+		t.Errorf("Lint() of a clean matcher = %v, want none", got)
+	}
+}
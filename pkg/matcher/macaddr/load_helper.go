@@ -22,11 +22,15 @@ package macaddr
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
+	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pmkol/mosdns-x/pkg/data_provider"
 )
@@ -36,6 +40,11 @@ type LocalMatcher interface {
 	Match(mac net.HardwareAddr) bool
 	Len() int
 	Close() error
+	// Kind names the type of the matcher for diagnostics, e.g. "static"
+	// for a Matcher, "dynamic" for a DynamicMatcher and "group" for a
+	// LocalMatcherGroup. Kind was added to the interface after its first
+	// release, so implementations outside this package must add it.
+	Kind() string
 }
 
 // LocalWriteableMatcher defines the interface for adding MAC addresses in load_helper.
@@ -44,7 +53,8 @@ type LocalWriteableMatcher interface {
 	Add(pattern string, v struct{}) error
 }
 
-// Load loads a MAC address string to the matcher.
+// Load loads a MAC address string to the matcher. It may be any pattern
+// accepted by Matcher.Add, whose kind is detected by its syntax.
 func Load(m LocalWriteableMatcher, s string) error {
 	s = strings.TrimSpace(s)
 	if len(s) == 0 {
@@ -63,20 +73,82 @@ func BatchLoad(m LocalWriteableMatcher, b []string) error {
 	return nil
 }
 
+// BatchLoadCounted is like BatchLoad, but also reports how many entries were
+// newly added and how many were duplicates of existing ones, judged by the
+// change of m.Len(). Blank entries count as neither.
+func BatchLoadCounted(m LocalWriteableMatcher, b []string) (added, duplicate int, err error) {
+	for _, s := range b {
+		if len(strings.TrimSpace(s)) == 0 {
+			continue
+		}
+		n := m.Len()
+		if err := Load(m, s); err != nil {
+			return added, duplicate, fmt.Errorf("failed to load data %s: %w", s, err)
+		}
+		if m.Len() > n {
+			added++
+		} else {
+			duplicate++
+		}
+	}
+	return added, duplicate, nil
+}
+
+// LocalMatcherGroup matches a MAC if any matcher in the group matches it
+// and no matcher in its exclude set does.
 type LocalMatcherGroup struct {
-	g      []LocalMatcher
-	closer []func()
+	g       []LocalMatcher
+	exclude []LocalMatcher
+	closer  []func()
 }
 
+// Close detaches the group from its data providers first, then closes all
+// matchers, including the exclude set. Errors are joined.
 func (mg *LocalMatcherGroup) Close() error {
 	for _, f := range mg.closer {
 		f()
 	}
-	return nil
+	var errs []error
+	for _, m := range mg.g {
+		if err := m.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, m := range mg.exclude {
+		if err := m.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func (mg *LocalMatcherGroup) Match(mac net.HardwareAddr) bool {
 	for _, m := range mg.g {
+		if m.Match(mac) {
+			return !mg.excluded(mac)
+		}
+	}
+	return false
+}
+
+// MatchDetailed is like Match, but also returns the index of the first
+// matcher in the group, in the order they were appended, that matched.
+// index is -1 if ok is false.
+func (mg *LocalMatcherGroup) MatchDetailed(mac net.HardwareAddr) (index int, ok bool) {
+	for i, m := range mg.g {
+		if m.Match(mac) {
+			if mg.excluded(mac) {
+				return -1, false
+			}
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// excluded reports whether mac is matched by the exclude set.
+func (mg *LocalMatcherGroup) excluded(mac net.HardwareAddr) bool {
+	for _, m := range mg.exclude {
 		if m.Match(mac) {
 			return true
 		}
@@ -84,6 +156,41 @@ func (mg *LocalMatcherGroup) Match(mac net.HardwareAddr) bool {
 	return false
 }
 
+// MatchOutcome returns OutcomeHit if any matcher in the group has a regular
+// hit, otherwise OutcomeGrace if any matcher is in its grace window for mac.
+// Excluded MACs always miss.
+func (mg *LocalMatcherGroup) MatchOutcome(mac net.HardwareAddr) Outcome {
+	if o := mg.matchOutcome(mac); o != OutcomeMiss && !mg.excluded(mac) {
+		return o
+	}
+	return OutcomeMiss
+}
+
+func (mg *LocalMatcherGroup) matchOutcome(mac net.HardwareAddr) Outcome {
+	res := OutcomeMiss
+	for _, m := range mg.g {
+		switch o := matchOutcome(m, mac); o {
+		case OutcomeHit:
+			return o
+		case OutcomeGrace:
+			res = o
+		}
+	}
+	return res
+}
+
+// SetGracePeriod sets the grace window of all DynamicMatcher in the group.
+// See DynamicMatcher.SetGracePeriod.
+func (mg *LocalMatcherGroup) SetGracePeriod(g time.Duration) {
+	for _, m := range mg.g {
+		if d, ok := m.(*DynamicMatcher); ok {
+			d.SetGracePeriod(g)
+		}
+	}
+}
+
+// Len returns the total length of the matchers in the group.
+// The exclude set is not counted.
 func (mg *LocalMatcherGroup) Len() int {
 	s := 0
 	for _, m := range mg.g {
@@ -96,83 +203,429 @@ func (mg *LocalMatcherGroup) Append(nm LocalMatcher) {
 	mg.g = append(mg.g, nm)
 }
 
+// AppendExclude adds nm to the exclude set. MACs matched by nm never match
+// the group. nm is closed when the group is closed.
+func (mg *LocalMatcherGroup) AppendExclude(nm LocalMatcher) {
+	mg.exclude = append(mg.exclude, nm)
+}
+
 func (mg *LocalMatcherGroup) AppendCloser(f func()) {
 	mg.closer = append(mg.closer, f)
 }
 
 // BatchLoadMacProvider loads multiple data entries.
+// An entry is a MAC pattern, "provider:tag" for a data provider of MAC
+// files, "lease:tag" for a data provider of a dnsmasq lease file, or
+// "file:path" for a local MAC file that is read once without a provider.
 // Caller must call LocalMatcherGroup.Close to detach this matcher from data_provider.DataManager to avoid leaking.
 func BatchLoadMacProvider(
 	e []string,
 	dm *data_provider.DataManager,
+) (*LocalMatcherGroup, error) {
+	return BatchLoadMacProviderSites(e, dm, nil)
+}
+
+// BatchLoadMacProviderSites is like BatchLoadMacProvider, but only loads
+// entries whose site column is in sites. See LoadFromTextReaderSites.
+func BatchLoadMacProviderSites(
+	e []string,
+	dm *data_provider.DataManager,
+	sites []string,
+) (*LocalMatcherGroup, error) {
+	return batchLoadMacProvider(e, dm, newSiteFilter(sites), 0, NewMatcher())
+}
+
+// BatchLoadMacProviderWith is like BatchLoadMacProvider, but loads the
+// entries without a scheme into seed instead of a fresh matcher. seed
+// may already hold entries, and it is appended to the group first, so
+// it is checked before any provider.
+func BatchLoadMacProviderWith(
+	e []string,
+	dm *data_provider.DataManager,
+	seed *Matcher,
+) (*LocalMatcherGroup, error) {
+	return batchLoadMacProvider(e, dm, nil, 0, seed)
+}
+
+func batchLoadMacProvider(
+	e []string,
+	dm *data_provider.DataManager,
+	sf siteFilter,
+	maxEntries int,
+	staticMatcher *Matcher,
 ) (*LocalMatcherGroup, error) {
 	mg := &LocalMatcherGroup{}
-	staticMatcher := NewMatcher()
 	mg.Append(staticMatcher)
 
 	for _, s := range e {
-		if strings.HasPrefix(s, "provider:") {
-			providerTag := strings.TrimPrefix(s, "provider:")
-			provider := dm.GetDataProvider(providerTag)
-			if provider == nil {
-				return nil, fmt.Errorf("cannot find provider %s", providerTag)
+		scheme, ref, ok := cutScheme(s)
+		if !ok {
+			if err := loadSite(staticMatcher, s, sf, ""); err != nil {
+				_ = mg.Close()
+				return nil, fmt.Errorf("failed to load data %s: %w", s, err)
 			}
-			parseFunc := func(b []byte) (LocalMatcher, error) {
-				return ParseTextMacFile(b)
+			continue
+		}
+		m, closer, err := loadSource(s, scheme, ref, dm, sf, maxEntries)
+		if err != nil {
+			_ = mg.Close()
+			return nil, err
+		}
+		if fm, ok := m.(*Matcher); ok && staticMatcher.addFrom(fm) {
+			continue // a static source, e.g. a file, see loadSource
+		}
+		mg.Append(m)
+		if closer != nil {
+			mg.AppendCloser(closer)
+		}
+	}
+	return mg, nil
+}
+
+// loadSource loads the entry s with a scheme. If the returned closer is not
+// nil, it detaches the matcher from its data provider. Static sources,
+// which never update, e.g. files, are not wrapped in a DynamicMatcher, so
+// the caller may fold them into its static matcher. See
+// checkMaxEntries for maxEntries.
+func loadSource(
+	s, scheme, ref string,
+	dm *data_provider.DataManager,
+	sf siteFilter,
+	maxEntries int,
+) (LocalMatcher, func(), error) {
+	ref, format, err := cutFormat(ref)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid entry %s, %w", s, err)
+	}
+	if scheme == "lease" && len(format) == 0 {
+		format = "dhcp-lease"
+	}
+	macFileParser := func(b []byte) (LocalMatcher, error) {
+		return parseMacFile(b, sf, maxEntries)
+	}
+	if len(format) > 0 {
+		fn, err := getMacParser(format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid entry %s, %w", s, err)
+		}
+		macFileParser = func(b []byte) (LocalMatcher, error) {
+			m, err := fn(b, sf)
+			if err != nil {
+				return nil, err
 			}
-			dmMatcher := NewDynamicMatcher(parseFunc)
-			if err := provider.LoadAndAddListener(dmMatcher); err != nil {
-				return nil, fmt.Errorf("failed to load data from provider %s, %w", providerTag, err)
+			if err := checkMaxEntries(m, maxEntries); err != nil {
+				return nil, err
 			}
-			mg.Append(dmMatcher)
-			mg.AppendCloser(func() {
-				provider.DeleteListener(dmMatcher)
-			})
+			return m, nil
+		}
+	}
+	switch scheme {
+	case "provider", "lease":
+	case "file":
+		b, err := os.ReadFile(ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read file %s, %w", ref, err)
+		}
+		fileMatcher, err := macFileParser(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load data from file %s, %w", ref, err)
+		}
+		return fileMatcher, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown scheme %s in %s", scheme, s)
+	}
+
+	providerTag := ref
+	provider := dm.GetDataProvider(providerTag)
+	if provider == nil {
+		return nil, nil, fmt.Errorf("cannot find provider %s, available providers: %v", providerTag, dm.ListProviderTags())
+	}
+	dmMatcher := NewDynamicMatcher(macFileParser)
+	if err := provider.LoadAndAddListener(dmMatcher); err != nil {
+		return nil, nil, fmt.Errorf("failed to load data from provider %s, %w", providerTag, err)
+	}
+	return dmMatcher, func() { provider.DeleteListener(dmMatcher) }, nil
+}
+
+// ValidatePatterns checks the entries of BatchLoadMacProvider without
+// loading them and returns an error for every bad entry, so all of them
+// can be fixed at once. MAC patterns are checked with the same rules as
+// Matcher.Add. Entries with a known scheme are not loaded and only their
+// scheme is checked.
+func ValidatePatterns(patterns []string) []error {
+	var errs []error
+	scratch := NewMatcher()
+	for i, s := range patterns {
+		var err error
+		if scheme, ref, ok := cutScheme(s); ok {
+			err = validateSource(scheme, ref)
 		} else {
-			if err := Load(staticMatcher, s); err != nil {
-				return nil, fmt.Errorf("failed to load data %s: %w", s, err)
-			}
+			err = loadSite(scratch, s, nil, "")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry #%d %q: %w", i, s, err))
 		}
 	}
-	return mg, nil
+	return errs
 }
 
+// validateSource checks the scheme and the format of an entry with a
+// scheme, without loading it.
+func validateSource(scheme, ref string) error {
+	if !isKnownScheme(scheme) {
+		return fmt.Errorf("unknown scheme %s", scheme)
+	}
+	_, format, err := cutFormat(ref)
+	if err != nil || len(format) == 0 {
+		return err
+	}
+	_, err = getMacParser(format)
+	return err
+}
+
+func isKnownScheme(scheme string) bool {
+	switch scheme {
+	case "provider", "lease", "file":
+		return true
+	default:
+		return false
+	}
+}
+
+// cutScheme cuts the "scheme:" prefix of a BatchLoadMacProvider entry.
+// Since MAC addresses also contain ':', only a prefix of at least three
+// characters starting with a letter, which can never be a MAC octet,
+// is a scheme.
+func cutScheme(s string) (scheme, ref string, ok bool) {
+	scheme, ref, ok = strings.Cut(s, ":")
+	if !ok || len(scheme) < 3 || !isLetter(scheme[0]) {
+		return "", "", false
+	}
+	for i := 1; i < len(scheme); i++ {
+		if c := scheme[i]; !isLetter(c) && !('0' <= c && c <= '9') && c != '_' {
+			return "", "", false
+		}
+	}
+	return scheme, ref, true
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// defaultCommentPrefixes is used when no comment prefix is given.
+var defaultCommentPrefixes = []string{"#"}
+
 // LoadFromTextReader loads multiple lines from reader r.
+// A trailing comment, e.g. "aa:bb:cc:dd:ee:ff # living-room-tv", becomes
+// the tag of the entry if m supports tags. See Matcher.AddWithTag.
 func LoadFromTextReader(m LocalWriteableMatcher, r io.Reader) error {
+	return loadFromTextReader(m, r, nil, defaultCommentPrefixes, nil, nil, 0)
+}
+
+// LoadFromTextReaderOpts is like LoadFromTextReader, but treats any of
+// commentPrefixes as the start of a comment. Lines starting with a prefix
+// are skipped, and everything after the first prefix on other lines is
+// dropped. If commentPrefixes is empty, "#" is used.
+func LoadFromTextReaderOpts(m LocalWriteableMatcher, r io.Reader, commentPrefixes []string) error {
+	if len(commentPrefixes) == 0 {
+		commentPrefixes = defaultCommentPrefixes
+	}
+	return loadFromTextReader(m, r, nil, commentPrefixes, nil, nil, 0)
+}
+
+// LoadFromTextReaderSites loads multiple lines from reader r. Each line may
+// carry a site code as a second column, e.g. "aa:bb:cc:dd:ee:ff site-a".
+// Only entries whose site is in sites are loaded. Entries without a site
+// column are shared by all sites and are always loaded.
+// If sites is empty, entries of all sites are loaded.
+func LoadFromTextReaderSites(m LocalWriteableMatcher, r io.Reader, sites []string) error {
+	return loadFromTextReader(m, r, newSiteFilter(sites), defaultCommentPrefixes, nil, nil, 0)
+}
+
+// DefaultMaxLineLen is the length limit of a line of a text mac file,
+// unless set by LoadFromTextReaderMaxLine.
+const DefaultMaxLineLen = 64 * 1024
+
+// ErrLineTooLong is returned if a line of a text mac file exceeds the
+// length limit, e.g. because the source is not a mac list at all.
+var ErrLineTooLong = errors.New("line exceeds the length limit")
+
+// LoadFromTextReaderMaxLine is like LoadFromTextReader, but limits the
+// length of a line to maxLineLen bytes instead of DefaultMaxLineLen.
+// A longer line fails the load with ErrLineTooLong.
+func LoadFromTextReaderMaxLine(m LocalWriteableMatcher, r io.Reader, maxLineLen int) error {
+	return loadFromTextReader(m, r, nil, defaultCommentPrefixes, nil, nil, maxLineLen)
+}
+
+// loadFromTextReader loads lines from r. If skip is nil, it stops at the
+// first bad line. Otherwise bad lines are passed to skip and skipped.
+// If inc is not nil, "@include" lines are loaded, see LoadFromTextReaderDir.
+// Lines are limited to maxLineLen bytes, or DefaultMaxLineLen if it is 0.
+func loadFromTextReader(
+	m LocalWriteableMatcher,
+	r io.Reader,
+	sf siteFilter,
+	commentPrefixes []string,
+	skip func(line int, err error),
+	inc *includer,
+	maxLineLen int,
+) error {
+	if maxLineLen <= 0 {
+		maxLineLen = DefaultMaxLineLen
+	}
 	lineCounter := 0
 	scanner := bufio.NewScanner(r)
+	// One more byte for the line terminator, which the scanner needs to
+	// see to end a line.
+	scanner.Buffer(make([]byte, 0, min(4096, maxLineLen+1)), maxLineLen+1)
 	for scanner.Scan() {
 		lineCounter++
-		s := scanner.Text()
+		s, comment := cutComment(scanner.Text(), commentPrefixes)
 		s = strings.TrimSpace(s)
-		if len(s) == 0 || strings.HasPrefix(s, "#") {
+		if len(s) == 0 {
 			continue
 		}
-		if err := Load(m, s); err != nil {
+		if inc != nil {
+			if path, ok := cutInclude(s); ok {
+				if err := inc.load(m, path, sf, commentPrefixes, skip); err != nil {
+					return fmt.Errorf("line %d: %w", lineCounter, err)
+				}
+				continue
+			}
+		}
+		if err := loadSite(m, s, sf, strings.TrimSpace(comment)); err != nil {
+			if skip != nil && !errors.Is(err, ErrTooManyEntries) {
+				skip(lineCounter, err)
+				continue
+			}
 			return fmt.Errorf("line %d: %w", lineCounter, err)
 		}
 	}
-	return scanner.Err()
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return fmt.Errorf("line %d: %w of %d bytes", lineCounter+1, ErrLineTooLong, maxLineLen)
+		}
+		return err
+	}
+	return nil
 }
 
 type DynamicMatcher struct {
 	parserFunc func(b []byte) (LocalMatcher, error)
 	l          sync.RWMutex
 	m          LocalMatcher
+
+	// OnUpdate, if not nil, is called after each Update with the entry
+	// count of the new data, or with the parse error. On error the previous
+	// data is kept and n is 0.
+	OnUpdate func(n int, err error)
+	// OnDiff, if not nil, is called after each successful Update with the
+	// changes from the previous data. See MatcherDiff. It is only called if
+	// both the previous and the new data are *Matcher.
+	OnDiff func(added, removed [][6]byte)
+	// RejectEmptyUpdates makes Update fail with ErrEmptyUpdate and keep the
+	// previous data if the new data has no entries, e.g. because the source
+	// was momentarily empty. OnUpdate is notified of the error.
+	RejectEmptyUpdates bool
+	// Sink, if not nil, is synced with the EUI-48 entries after each
+	// successful Update, see MatchSink. If the sync fails, the previous
+	// data is restored and Update fails.
+	Sink MatchSink
+
+	grace     time.Duration
+	removed   map[[6]byte]time.Time // removal time of entries still in grace
+	now       func() time.Time
+	observer  MatchObserver
+	cacheFile string
 }
 
+// ErrEmptyUpdate is returned by DynamicMatcher.Update if RejectEmptyUpdates
+// is set and the new data has no entries.
+var ErrEmptyUpdate = errors.New("update has no entries, previous data is kept")
+
 func NewDynamicMatcher(parserFunc func(b []byte) (LocalMatcher, error)) *DynamicMatcher {
-	return &DynamicMatcher{parserFunc: parserFunc}
+	return &DynamicMatcher{parserFunc: parserFunc, now: time.Now}
+}
+
+// NewDynamicMatcherWithHook is like NewDynamicMatcher, but sets OnUpdate to hook.
+func NewDynamicMatcherWithHook(
+	parserFunc func(b []byte) (LocalMatcher, error),
+	hook func(n int, err error),
+) *DynamicMatcher {
+	d := NewDynamicMatcher(parserFunc)
+	d.OnUpdate = hook
+	return d
 }
 
 func (d *DynamicMatcher) Match(mac net.HardwareAddr) bool {
+	return d.MatchOutcome(mac) != OutcomeMiss
+}
+
+// SetGracePeriod enables the grace window. An entry that disappears from
+// the data on Update keeps matching with OutcomeGrace for g after its
+// removal. Only removals between two *Matcher loads are tracked.
+// g <= 0 disables the grace window.
+func (d *DynamicMatcher) SetGracePeriod(g time.Duration) {
+	d.l.Lock()
+	defer d.l.Unlock()
+	d.grace = g
+	if g > 0 {
+		d.removed = make(map[[6]byte]time.Time)
+	} else {
+		d.removed = nil
+	}
+}
+
+// MatchOutcome is like Match, but tells a regular hit from a hit of a
+// recently removed entry. See SetGracePeriod.
+func (d *DynamicMatcher) MatchOutcome(mac net.HardwareAddr) Outcome {
 	d.l.RLock()
 	m := d.m
+	grace := d.grace
+	observer := d.observer
+	var removedAt time.Time
+	var inGrace bool
+	if grace > 0 && len(mac) == 6 {
+		removedAt, inGrace = d.removed[[6]byte(mac)]
+	}
 	d.l.RUnlock()
-	if m == nil {
-		return false
+
+	res := OutcomeMiss
+	switch {
+	case m != nil && m.Match(mac):
+		res = OutcomeHit
+	case inGrace && d.now().Sub(removedAt) < grace:
+		res = OutcomeGrace
+	}
+	if observer != nil {
+		observer.ObserveMatch(res != OutcomeMiss)
+	}
+	return res
+}
+
+// trackRemoved records entries of old that are absent from nm and drops
+// expired or re-added ones. Caller must hold the write lock.
+func (d *DynamicMatcher) trackRemoved(old, nm LocalMatcher) {
+	oldM, ok := old.(*Matcher)
+	if !ok {
+		return
+	}
+	newM, ok := nm.(*Matcher)
+	if !ok {
+		return
+	}
+	now := d.now()
+	for k := range oldM.macs {
+		if _, ok := newM.macs[k]; !ok {
+			d.removed[k] = now
+		}
+	}
+	for k, t := range d.removed {
+		if _, ok := newM.macs[k]; ok || now.Sub(t) >= d.grace {
+			delete(d.removed, k)
+		}
 	}
-	return m.Match(mac)
 }
 
 func (d *DynamicMatcher) Len() int {
@@ -196,25 +649,301 @@ func (d *DynamicMatcher) Close() error {
 }
 
 func (d *DynamicMatcher) Update(b []byte) error {
+	if err := d.update(b); err != nil {
+		return err
+	}
+	d.l.RLock()
+	cacheFile := d.cacheFile
+	d.l.RUnlock()
+	if len(cacheFile) > 0 {
+		if err := writeFileAtomic(cacheFile, b); err != nil {
+			return fmt.Errorf("data updated, but failed to write cache file, %w", err)
+		}
+	}
+	return nil
+}
+
+// update parses b into a new matcher, validates it, swaps it in and then
+// syncs the sink, if any. If the sink fails, the previous matcher is
+// restored, so the matcher and the sink never disagree for long.
+// Hooks are only notified of the outcome.
+func (d *DynamicMatcher) update(b []byte) error {
 	m, err := d.parserFunc(b)
+	if err == nil && d.RejectEmptyUpdates && m.Len() == 0 {
+		err = ErrEmptyUpdate
+	}
 	if err != nil {
+		d.updateFailed(err)
 		return err
 	}
+
 	d.l.Lock()
+	old := d.m
+	var oldRemoved map[[6]byte]time.Time
+	if d.grace > 0 {
+		if d.Sink != nil {
+			oldRemoved = maps.Clone(d.removed)
+		}
+		d.trackRemoved(old, m)
+	}
 	d.m = m
 	d.l.Unlock()
+
+	if d.Sink != nil {
+		if err := syncSink(d.Sink, m); err != nil {
+			d.l.Lock()
+			d.m = old
+			if d.grace > 0 {
+				d.removed = oldRemoved
+			}
+			d.l.Unlock()
+			err = errors.Join(
+				fmt.Errorf("failed to sync sink, %w", err),
+				m.Close(),
+			)
+			d.updateFailed(err)
+			return err
+		}
+	}
+
+	logger().Infof("mac list updated, %d entries", m.Len())
+	if d.OnUpdate != nil {
+		d.OnUpdate(m.Len(), nil)
+	}
+	if d.OnDiff != nil {
+		oldM, ok1 := old.(*Matcher)
+		newM, ok2 := m.(*Matcher)
+		if ok1 && ok2 {
+			d.OnDiff(MatcherDiff(oldM, newM))
+		}
+	}
 	return nil
 }
 
-// ParseTextMacFile parses MAC addresses from text bytes.
+// updateFailed logs err of a failed update and notifies OnUpdate.
+func (d *DynamicMatcher) updateFailed(err error) {
+	logger().Warnf("failed to update mac list, previous data is kept, %v", err)
+	if d.OnUpdate != nil {
+		d.OnUpdate(0, err)
+	}
+}
+
+// ParseTextMacFile parses MAC addresses from text bytes, which may be gzip compressed.
 func ParseTextMacFile(in []byte) (*Matcher, error) {
-	m := NewMatcher()
-	if err := LoadFromTextReader(m, bytes.NewReader(in)); err != nil {
+	return parseTextMacFile(in, nil, 0, nil)
+}
+
+// countDataLines counts the lines of in that are neither blank nor "#"
+// comments. It is a cheap upper bound of the entries of a text mac file.
+func countDataLines(in []byte) int {
+	n := 0
+	for len(in) > 0 {
+		line, rest, _ := bytes.Cut(in, []byte{'\n'})
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && line[0] != '#' {
+			n++
+		}
+		in = rest
+	}
+	return n
+}
+
+// LineError is a bad line of a text mac file.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ParseTextMacFileLenient is like ParseTextMacFile, but skips bad lines
+// instead of failing on the first one. The skipped lines are returned as
+// problems. err is only returned if the data cannot be read at all, e.g.
+// for corrupted gzip data.
+func ParseTextMacFileLenient(in []byte) (m *Matcher, problems []*LineError, err error) {
+	m, err = parseTextMacFile(in, nil, 0, func(line int, err error) {
+		logger().Warnf("skipped invalid mac list line %d, %v", line, err)
+		problems = append(problems, &LineError{Line: line, Err: err})
+	})
+	if err != nil {
+		return nil, problems, err
+	}
+	return m, problems, nil
+}
+
+// ParseTextMacFileSites parses MAC addresses from text bytes, keeping only
+// entries of the given sites. See LoadFromTextReaderSites.
+func ParseTextMacFileSites(in []byte, sites []string) (*Matcher, error) {
+	return parseTextMacFile(in, newSiteFilter(sites), 0, nil)
+}
+
+// parseMacFile parses in as a binary mac file if it has the binary magic
+// header, as a JSON file if it is a JSON object, or as a text file
+// otherwise. Site filtering only applies to text.
+// Gzip compressed input is decompressed first.
+// See checkMaxEntries for maxEntries.
+func parseMacFile(in []byte, sf siteFilter, maxEntries int) (*Matcher, error) {
+	if isGzip(in) {
+		b, err := gunzip(in)
+		if err != nil {
+			return nil, err
+		}
+		in = b
+	}
+	var m *Matcher
+	var err error
+	switch {
+	case isBinaryMacFile(in):
+		m, err = ParseBinaryMacFile(in)
+	case isJSONMacFile(in):
+		m, err = ParseJSONMacFile(in)
+	default:
+		return parseTextMacFile(in, sf, maxEntries, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMaxEntries(m, maxEntries); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
+// parseTextMacFile parses in as a text mac file. Gzip compressed input is
+// decompressed while it is read. Parsing stops once the matcher has more
+// than maxEntries entries, see checkMaxEntries. See loadFromTextReader
+// for skip.
+func parseTextMacFile(
+	in []byte,
+	sf siteFilter,
+	maxEntries int,
+	skip func(line int, err error),
+) (*Matcher, error) {
+	var r io.Reader = bytes.NewReader(in)
+	hint := 0
+	if isGzip(in) {
+		gr, err := newGzipReader(in)
+		if err != nil {
+			return nil, err
+		}
+		r = gr
+	} else {
+		hint = countDataLines(in)
+	}
+	if maxEntries > 0 {
+		hint = min(hint, maxEntries)
+	}
+	m := NewMatcherSize(hint)
+	var w LocalWriteableMatcher = m
+	if maxEntries > 0 {
+		w = limitedMatcher{Matcher: m, max: maxEntries}
+	}
+	if err := loadFromTextReader(w, r, sf, defaultCommentPrefixes, skip, nil, 0); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// cutComment cuts s at the first occurrence of any of prefixes and returns
+// the text before and after it. comment is empty if s has no comment.
+func cutComment(s string, prefixes []string) (line, comment string) {
+	cut := -1
+	var prefix string
+	for _, p := range prefixes {
+		if i := strings.Index(s, p); i >= 0 && (cut < 0 || i < cut) {
+			cut, prefix = i, p
+		}
+	}
+	if cut < 0 {
+		return s, ""
+	}
+	return s[:cut], s[cut+len(prefix):]
+}
+
+// siteFilter is a set of site codes. A nil siteFilter keeps all sites.
+type siteFilter map[string]struct{}
+
+func newSiteFilter(sites []string) siteFilter {
+	if len(sites) == 0 {
+		return nil
+	}
+	sf := make(siteFilter, len(sites))
+	for _, site := range sites {
+		sf[site] = struct{}{}
+	}
+	return sf
+}
+
+// keep reports whether an entry of site should be loaded.
+// Entries without a site are always kept.
+func (sf siteFilter) keep(site string) bool {
+	if sf == nil || len(site) == 0 {
+		return true
+	}
+	_, ok := sf[site]
+	return ok
+}
+
+// taggedMatcher is implemented by matchers that can store a tag per entry.
+type taggedMatcher interface {
+	AddWithTag(pattern, tag string) error
+}
+
+// loadSite loads s, which is "mac [site]", to m if its site is kept by sf.
+// A line of more than two fields is a row of a mac address table, see
+// tableMAC. Note that a two fields row like "mac port" is still read as
+// "mac site", so its port is matched against sf.
+// If tag is not empty and m is a taggedMatcher, the entry is tagged with it.
+func loadSite(m LocalWriteableMatcher, s string, sf siteFilter, tag string) error {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 0:
+		return nil
+	case 1:
+		return loadTagged(m, fields[0], tag)
+	case 2:
+		if !sf.keep(fields[1]) {
+			return nil
+		}
+		return loadTagged(m, fields[0], tag)
+	default:
+		if mac, ok := tableMAC(fields); ok {
+			return loadTagged(m, mac, tag)
+		}
+		return fmt.Errorf("invalid line %q, expect \"mac [site]\" or a mac address table row", s)
+	}
+}
+
+// tableMAC returns the MAC column of a row of a mac address table, e.g.
+// the output of Cisco "show mac address-table", whose columns are
+// "VLAN MAC type port". It is the first field that is a single MAC
+// address, which may also be the first column, e.g. "MAC type port".
+func tableMAC(fields []string) (string, bool) {
+	for _, f := range fields {
+		if k, err := ClassifyPattern(f); err == nil && k == KindExact {
+			return f, true
+		}
+	}
+	return "", false
+}
+
+func loadTagged(m LocalWriteableMatcher, s, tag string) error {
+	if tm, ok := m.(taggedMatcher); ok && len(tag) > 0 {
+		return tm.AddWithTag(s, tag)
+	}
+	return Load(m, s)
+}
+
+func (mg *LocalMatcherGroup) Kind() string { return "group" }
+
+func (d *DynamicMatcher) Kind() string { return "dynamic" }
+
 // Ensure LocalMatcherGroup implements LocalMatcher.
 var _ LocalMatcher = (*LocalMatcherGroup)(nil)
 
@@ -0,0 +1,631 @@
+package macaddr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/pmkol/mosdns-x/pkg/data_provider"
+)
+
+func TestLoadFromTextReaderSites(t *testing.T) {
+	const data = `
+# shared
+00:11:22:33:44:55
+aa:bb:cc:dd:ee:01 site-a
+aa:bb:cc:dd:ee:02 site-b
+aa:bb:cc:dd:ee:03 site-c
+`
+	tests := []struct {
+		name  string
+		sites []string
+		want  []string
+		miss  []string
+	}{
+		{
+			name:  "all sites",
+			sites: nil,
+			want:  []string{"00:11:22:33:44:55", "aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:02", "aa:bb:cc:dd:ee:03"},
+		},
+		{
+			name:  "single site",
+			sites: []string{"site-a"},
+			want:  []string{"00:11:22:33:44:55", "aa:bb:cc:dd:ee:01"},
+			miss:  []string{"aa:bb:cc:dd:ee:02", "aa:bb:cc:dd:ee:03"},
+		},
+		{
+			name:  "multiple sites",
+			sites: []string{"site-b", "site-c"},
+			want:  []string{"00:11:22:33:44:55", "aa:bb:cc:dd:ee:02", "aa:bb:cc:dd:ee:03"},
+			miss:  []string{"aa:bb:cc:dd:ee:01"},
+		},
+		{
+			name:  "unknown site",
+			sites: []string{"site-x"},
+			want:  []string{"00:11:22:33:44:55"},
+			miss:  []string{"aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:02", "aa:bb:cc:dd:ee:03"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher()
+			if err := LoadFromTextReaderSites(m, strings.NewReader(data), tt.sites); err != nil {
+				t.Fatal(err)
+			}
+			if m.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", m.Len(), len(tt.want))
+			}
+			for _, s := range tt.want {
+				if !m.Match(mustParseMAC(s)) {
+					t.Errorf("%s should match", s)
+				}
+			}
+			for _, s := range tt.miss {
+				if m.Match(mustParseMAC(s)) {
+					t.Errorf("%s should not match", s)
+				}
+			}
+		})
+	}
+
+	if err := LoadFromTextReaderSites(NewMatcher(), strings.NewReader("site-a a b"), nil); err == nil {
+		t.Error("line with too many columns and no mac should fail")
+	}
+}
+
+func TestLoadFromTextReader_MixedNotations(t *testing.T) {
+	const data = `
+AA-BB-CC-DD-EE-FF
+aabb.ccdd.eeff
+aa:bb:cc:dd:ee:ff
+AaBb.CcDd.EeFf
+AABBCCDDEEFF
+`
+	m := NewMatcher()
+	if err := LoadFromTextReader(m, strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 1 {
+		t.Errorf("LoadFromTextReader() Len() = %d, want 1", m.Len())
+	}
+
+	pm, err := ParseTextMacFile([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pm.Len() != 1 {
+		t.Errorf("ParseTextMacFile() Len() = %d, want 1", pm.Len())
+	}
+	if !pm.Match(mustParseMAC("aa:bb:cc:dd:ee:ff")) {
+		t.Error("normalized MAC should match")
+	}
+}
+
+func TestMatcher_AddBareHex(t *testing.T) {
+	m := NewMatcher()
+	if err := m.Add("AABBCC001122", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("aa:bb:cc:00:11:22", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d, want 1, bare and colon forms should be the same key", m.Len())
+	}
+	if !m.Match(mustParseMAC("aa:bb:cc:00:11:22")) {
+		t.Error("bare hex MAC should match")
+	}
+	if err := m.Add("00112233445g", struct{}{}); err == nil {
+		t.Error("Add() should reject a 12-character token with non-hex digits")
+	}
+	if err := LoadFromTextReader(NewMatcher(), strings.NewReader("0011-2233445\n")); err == nil {
+		t.Error("LoadFromTextReader() should reject a 12-character token with a separator")
+	}
+}
+
+func TestLoadFromTextReader_CiscoTable(t *testing.T) {
+	const data = `
+# Vlan    Mac Address       Type        Ports
+  10    0011.2233.4455    DYNAMIC     Gi1/0/1
+  20    AABB.CCDD.EEFF    STATIC      Gi1/0/2 
+   1    00-11-22-33-44-66 DYNAMIC     Po1
+`
+	m := NewMatcher()
+	if err := LoadFromTextReader(m, strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+	for _, s := range []string{"00:11:22:33:44:55", "aa:bb:cc:dd:ee:ff", "00:11:22:33:44:66"} {
+		if !m.Match(mustParseMAC(s)) {
+			t.Errorf("%s should match", s)
+		}
+	}
+
+	// The MAC column may also be the first one.
+	m = NewMatcher()
+	in := "0011.2233.4455 DYNAMIC Gi1/0/1\n00:11:22:33:44:66 Gi1/0/2 uplink\n"
+	if err := LoadFromTextReader(m, strings.NewReader(in)); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"00:11:22:33:44:55", "00:11:22:33:44:66"} {
+		if !m.Match(mustParseMAC(s)) {
+			t.Errorf("%s should match", s)
+		}
+	}
+
+	// A row without a MAC is still an error.
+	if err := LoadFromTextReader(NewMatcher(), strings.NewReader("10 DYNAMIC Gi1/0/1\n")); err == nil {
+		t.Error("expected error for a row without a mac")
+	}
+}
+
+// closeRecorder is a LocalMatcher that records its Close calls.
+type closeRecorder struct {
+	*Matcher
+	closed *[]string
+	name   string
+}
+
+func (c *closeRecorder) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return nil
+}
+
+func TestLocalMatcherGroup_Exclude(t *testing.T) {
+	provider := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	if err := provider.Update([]byte("00:11:22:33:44:55\n00:11:22:33:44:66\naa:bb:cc\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var closed []string
+	mg := &LocalMatcherGroup{}
+	mg.Append(provider)
+	mg.AppendExclude(&closeRecorder{Matcher: newTestMatcher(t, "00:11:22:33:44:66", "aa:bb:cc:00:00:01"), closed: &closed, name: "exclude"})
+
+	tests := []struct {
+		mac  string
+		want bool
+	}{
+		{"00:11:22:33:44:55", true},
+		{"00:11:22:33:44:66", false},
+		{"aa:bb:cc:00:00:02", true},
+		{"aa:bb:cc:00:00:01", false},
+		{"01:02:03:04:05:06", false},
+	}
+	for _, tt := range tests {
+		mac := mustParseMAC(tt.mac)
+		if got := mg.Match(mac); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.mac, got, tt.want)
+		}
+		if got := mg.MatchOutcome(mac) == OutcomeHit; got != tt.want {
+			t.Errorf("MatchOutcome(%s) hit = %v, want %v", tt.mac, got, tt.want)
+		}
+	}
+	if mg.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", mg.Len())
+	}
+
+	if err := mg.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 1 {
+		t.Errorf("exclude matcher should be closed, got %v", closed)
+	}
+}
+
+func TestDynamicMatcher_OnUpdate(t *testing.T) {
+	type call struct {
+		n   int
+		err error
+	}
+	var calls []call
+	d := NewDynamicMatcherWithHook(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	}, func(n int, err error) {
+		calls = append(calls, call{n, err})
+	})
+
+	if err := d.Update([]byte("00:11:22:33:44:55\n00:11:22:33:44:66\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Update([]byte("not-a-mac\n")); err == nil {
+		t.Fatal("expected parse error")
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("hook called %d times, want 2", len(calls))
+	}
+	if calls[0].n != 2 || calls[0].err != nil {
+		t.Errorf("first call = %+v, want n=2 and no error", calls[0])
+	}
+	if calls[1].n != 0 || calls[1].err == nil {
+		t.Errorf("second call = %+v, want n=0 and an error", calls[1])
+	}
+	if !d.Match(mustParseMAC("00:11:22:33:44:55")) || d.Len() != 2 {
+		t.Error("previous data should be retained after a failed update")
+	}
+}
+
+func TestDynamicMatcher_RejectEmptyUpdates(t *testing.T) {
+	for _, reject := range []bool{false, true} {
+		var hookErr error
+		d := NewDynamicMatcherWithHook(func(b []byte) (LocalMatcher, error) {
+			return ParseTextMacFile(b)
+		}, func(n int, err error) {
+			hookErr = err
+		})
+		d.RejectEmptyUpdates = reject
+
+		if err := d.Update([]byte("00:11:22:33:44:55\n00:11:22:33:44:66\n")); err != nil {
+			t.Fatal(err)
+		}
+		err := d.Update(nil)
+		if reject {
+			if !errors.Is(err, ErrEmptyUpdate) || !errors.Is(hookErr, ErrEmptyUpdate) {
+				t.Errorf("Update() error = %v, hook error = %v, want ErrEmptyUpdate", err, hookErr)
+			}
+			if d.Len() != 2 || !d.Match(mustParseMAC("00:11:22:33:44:55")) {
+				t.Error("previous data should be retained after an empty update")
+			}
+		} else {
+			if err != nil {
+				t.Errorf("Update() error = %v, want nil", err)
+			}
+			if d.Len() != 0 {
+				t.Errorf("Len() = %d, want 0 after an empty update", d.Len())
+			}
+		}
+	}
+}
+
+func TestLocalMatcherGroup_Close(t *testing.T) {
+	var events []string
+	errClose := errors.New("close failed")
+
+	mg := &LocalMatcherGroup{}
+	mg.Append(&closeRecorder{Matcher: NewMatcher(), closed: &events, name: "matcher"})
+	mg.Append(&failingCloser{Matcher: NewMatcher(), err: errClose})
+	mg.AppendCloser(func() { events = append(events, "detach") })
+
+	err := mg.Close()
+	if !errors.Is(err, errClose) {
+		t.Errorf("Close() error = %v, want %v", err, errClose)
+	}
+	if want := []string{"detach", "matcher"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("close order = %v, want %v", events, want)
+	}
+}
+
+type failingCloser struct {
+	*Matcher
+	err error
+}
+
+func (f *failingCloser) Close() error { return f.err }
+
+func TestBatchLoadCounted(t *testing.T) {
+	m := NewMatcher()
+	added, duplicate, err := BatchLoadCounted(m, []string{
+		"00:11:22:33:44:55",
+		"",
+		"00:11:22:33:44:66",
+		"  ",
+		"00-11-22-33-44-55",
+		"aa:bb:cc",
+		"AA:BB:CC",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 3 || duplicate != 2 {
+		t.Errorf("BatchLoadCounted() = %d added, %d duplicate, want 3, 2", added, duplicate)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+
+	if _, _, err := BatchLoadCounted(m, []string{"bad"}); err == nil {
+		t.Error("expected error for invalid entry")
+	}
+}
+
+func TestLoadFromTextReaderOpts(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefixes []string
+		data     string
+	}{
+		{"default", nil, "# comment\n00:11:22:33:44:55\n"},
+		{"semicolon", []string{";"}, "; comment\n00:11:22:33:44:55\n"},
+		{"double slash", []string{"//"}, "  // comment\n00:11:22:33:44:55\n"},
+		{"multiple", []string{";", "//"}, "; a\n// b\n00:11:22:33:44:55\n"},
+		{"inline", []string{"#", "//"}, "00:11:22:33:44:55 // laptop\n# comment\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher()
+			if err := LoadFromTextReaderOpts(m, strings.NewReader(tt.data), tt.prefixes); err != nil {
+				t.Fatal(err)
+			}
+			if m.Len() != 1 || !m.Match(mustParseMAC("00:11:22:33:44:55")) {
+				t.Errorf("got %d entries, want only 00:11:22:33:44:55", m.Len())
+			}
+		})
+	}
+
+	// ';' is not a comment prefix by default.
+	if err := LoadFromTextReader(NewMatcher(), strings.NewReader("; comment\n")); err == nil {
+		t.Error("expected error for ';' line with default prefixes")
+	}
+}
+
+func TestLoadFromTextReaderMaxLine(t *testing.T) {
+	const limit = 32
+	ok := "00:11:22:33:44:55 # " + strings.Repeat("x", limit-20) + "\n"
+	if err := LoadFromTextReaderMaxLine(NewMatcher(), strings.NewReader(ok), limit); err != nil {
+		t.Errorf("line of %d bytes should load, %v", len(ok)-1, err)
+	}
+
+	data := "00:11:22:33:44:55\n" + strings.Repeat("a", limit+1) + "\n"
+	err := LoadFromTextReaderMaxLine(NewMatcher(), strings.NewReader(data), limit)
+	if !errors.Is(err, ErrLineTooLong) {
+		t.Fatalf("error = %v, want ErrLineTooLong", err)
+	}
+	if want := "line 2: line exceeds the length limit of 32 bytes"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	long := "00:11:22:33:44:55 # " + strings.Repeat("x", DefaultMaxLineLen) + "\n"
+	if _, err := ParseTextMacFile([]byte(long)); !errors.Is(err, ErrLineTooLong) {
+		t.Errorf("ParseTextMacFile() error = %v, want ErrLineTooLong", err)
+	}
+}
+
+func TestLoadFromTextReader_CommentTags(t *testing.T) {
+	const data = `
+# devices
+aa:bb:cc:dd:ee:ff #  living-room-tv  
+00:11:22:33:44:55
+00:11:22:33:44:66 site-a # office printer
+66:77:88:99:aa:bb #
+`
+	m := NewMatcher()
+	if err := LoadFromTextReader(m, strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		mac string
+		tag string
+	}{
+		{"aa:bb:cc:dd:ee:ff", "living-room-tv"},
+		{"00:11:22:33:44:55", ""},
+		{"00:11:22:33:44:66", "office printer"},
+		{"66:77:88:99:aa:bb", ""},
+	}
+	for _, tt := range tests {
+		tag, ok := m.MatchWithTag(mustParseMAC(tt.mac))
+		if !ok || tag != tt.tag {
+			t.Errorf("MatchWithTag(%s) = %q, %v, want %q, true", tt.mac, tag, ok, tt.tag)
+		}
+	}
+}
+
+func TestBatchLoadMacProvider_UnknownProvider(t *testing.T) {
+	dm := data_provider.NewDataManager()
+	dm.AddDataProvider("office_macs", new(data_provider.DataProvider))
+	dm.AddDataProvider("home_macs", new(data_provider.DataProvider))
+
+	_, err := BatchLoadMacProvider([]string{"provider:ofice_macs"}, dm)
+	if err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+	if !strings.Contains(err.Error(), "[home_macs office_macs]") {
+		t.Errorf("error %q should list the available providers", err)
+	}
+}
+
+func TestParseTextMacFileLenient(t *testing.T) {
+	const data = "00:11:22:33:44:55\naa:bb:cc:dd:ee:ff:\n# comment\nnot-a-mac\n00:11:22:33:44:66\n"
+	m, problems, err := ParseTextMacFileLenient([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+	var lines []int
+	for _, p := range problems {
+		lines = append(lines, p.Line)
+	}
+	if want := []int{2, 4}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("problem lines = %v, want %v", lines, want)
+	}
+
+	if _, err := ParseTextMacFile([]byte(data)); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ParseTextMacFile() error = %v, want a line 2 error", err)
+	}
+}
+
+func FuzzParseTextMacFile(f *testing.F) {
+	f.Add([]byte("aa:bb:cc:dd:ee:ff:\n"))
+	f.Add([]byte("00:11:22:33:44:55 site-a # tv\naa:bb:cc\n"))
+	f.Add([]byte("aa:bb:*:dd:*:ff\n00:11:22:33:44:00-00:11:22:33:44:ff\n"))
+	f.Add([]byte{0x1f, 0x8b, 0x08})
+	f.Fuzz(func(t *testing.T, in []byte) {
+		m, err := ParseTextMacFile(in)
+		if err == nil && m == nil {
+			t.Fatal("nil matcher without error")
+		}
+		if _, _, err := ParseTextMacFileLenient(in); err != nil && !isGzip(in) {
+			t.Fatalf("lenient parse of plain text failed: %v", err)
+		}
+	})
+}
+
+func TestBatchLoadMacProvider_Schemes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macs.txt")
+	if err := os.WriteFile(path, []byte("aa:bb:cc:dd:ee:ff\naa:bb:cc:dd:ee:01\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mg, err := BatchLoadMacProvider([]string{
+		"00:11:22:33:44:55",
+		"00:11:22:33:44:00-00:11:22:33:44:0f",
+		"file:" + path,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"00:11:22:33:44:55", "00:11:22:33:44:01", "aa:bb:cc:dd:ee:ff"} {
+		if !mg.Match(mustParseMAC(s)) {
+			t.Errorf("group should match %s", s)
+		}
+	}
+	if mg.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", mg.Len())
+	}
+
+	for _, e := range []string{"http://example.com/macs.txt", "file:" + path + ".missing"} {
+		if _, err := BatchLoadMacProvider([]string{e}, nil); err == nil {
+			t.Errorf("expected error for %s", e)
+		}
+	}
+}
+
+func TestBatchLoadMacProviderWith(t *testing.T) {
+	seed := newTestMatcher(t, "00:11:22:33:44:55")
+	mg, err := BatchLoadMacProviderWith([]string{"aa:bb:cc:dd:ee:ff"}, nil, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"00:11:22:33:44:55", "aa:bb:cc:dd:ee:ff"} {
+		if i, ok := mg.MatchDetailed(mustParseMAC(s)); !ok || i != 0 {
+			t.Errorf("MatchDetailed(%s) = %d, %v, want 0, true", s, i, ok)
+		}
+	}
+	if seed.Len() != 2 {
+		t.Errorf("seed Len() = %d, want 2", seed.Len())
+	}
+}
+
+func TestBatchLoadMacProvider_FoldStatic(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "static.txt")
+	providerPath := filepath.Join(dir, "provider.txt")
+	if err := os.WriteFile(filePath, []byte("aa:bb:cc:dd:ee:ff tv-site\naa:bb:cc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(providerPath, []byte("66:77:88:99:aa:bb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dp, err := data_provider.NewDataProvider(zap.NewNop(), data_provider.DataProviderConfig{File: providerPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+	dm := data_provider.NewDataManager()
+	dm.AddDataProvider("dynamic", dp)
+
+	mg, err := BatchLoadMacProvider([]string{"00:11:22:33:44:55", "file:" + filePath, "provider:dynamic"}, dm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mg.Close()
+	if n := len(mg.g); n != 2 {
+		t.Fatalf("group has %d members, want 2", n)
+	}
+	if _, ok := mg.g[1].(*DynamicMatcher); !ok {
+		t.Errorf("second member is %T, want *DynamicMatcher", mg.g[1])
+	}
+	for _, s := range []string{"00:11:22:33:44:55", "aa:bb:cc:00:00:01", "66:77:88:99:aa:bb"} {
+		if !mg.Match(mustParseMAC(s)) {
+			t.Errorf("group should match %s", s)
+		}
+	}
+	if i, _ := mg.MatchDetailed(mustParseMAC("aa:bb:cc:dd:ee:ff")); i != 0 {
+		t.Errorf("file entry matched member %d, want the static member 0", i)
+	}
+}
+
+func TestLocalMatcher_Kind(t *testing.T) {
+	tests := []struct {
+		m    LocalMatcher
+		want string
+	}{
+		{NewMatcher(), "static"},
+		{NewDynamicMatcher(nil), "dynamic"},
+		{&LocalMatcherGroup{}, "group"},
+		{NewSyncMatcher(), "sync"},
+		{NewChainMatcher(), "chain"},
+		{NewAtomicGroup(), "atomic_group"},
+		{NewTrieMatcher(), "trie"},
+		{NewVendorMatcher(&OUIRegistry{}), "vendor"},
+		{MatchNone{}, "none"},
+		{MatchAll{}, "all"},
+		{&BitMatcher{}, "bit"},
+		{And(), "and"},
+		{Or(), "or"},
+		{Not(MatchNone{}), "not"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Kind(); got != tt.want {
+			t.Errorf("%T.Kind() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestLocalMatcherGroup_MatchDetailed(t *testing.T) {
+	mg := &LocalMatcherGroup{}
+	mg.Append(newTestMatcher(t, "00:11:22:33:44:01"))
+	mg.Append(newTestMatcher(t, "00:11:22:33:44:02", "00:11:22:33:44:03"))
+	mg.Append(newTestMatcher(t, "00:11:22:33:44:04"))
+	mg.AppendExclude(newTestMatcher(t, "00:11:22:33:44:03"))
+
+	tests := []struct {
+		mac       string
+		wantIndex int
+		wantOk    bool
+	}{
+		{"00:11:22:33:44:02", 1, true},
+		{"00:11:22:33:44:04", 2, true},
+		{"00:11:22:33:44:03", -1, false},
+		{"00:11:22:33:44:05", -1, false},
+	}
+	for _, tt := range tests {
+		index, ok := mg.MatchDetailed(mustParseMAC(tt.mac))
+		if index != tt.wantIndex || ok != tt.wantOk {
+			t.Errorf("MatchDetailed(%s) = %d, %v, want %d, %v", tt.mac, index, ok, tt.wantIndex, tt.wantOk)
+		}
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	errs := ValidatePatterns([]string{
+		"00:11:22:33:44:55",
+		"provider:office_macs",
+		"file:/etc/mosdns/macs.txt",
+		"aa:bb:cc:dd:ee:ff:",
+		"aa:bb:cc",
+		"00:11:22:33:44:55 site-a",
+		"not-a-mac",
+	})
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	if !strings.Contains(errs[0].Error(), "#3") || !strings.Contains(errs[1].Error(), "#6") {
+		t.Errorf("errors should tell the bad entries, got %v", errs)
+	}
+	if errs := ValidatePatterns([]string{"http://example.com"}); len(errs) != 1 {
+		t.Errorf("unknown scheme should be an error, got %v", errs)
+	}
+}
@@ -0,0 +1,36 @@
+package macaddr
+
+import "sync/atomic"
+
+// Logger logs the events of the package, e.g. skipped lines of lenient
+// parsing and the outcomes of DynamicMatcher updates.
+// *zap.SugaredLogger implements it.
+type Logger interface {
+	Warnf(template string, args ...interface{})
+	Infof(template string, args ...interface{})
+}
+
+var pkgLogger atomic.Pointer[Logger]
+
+// SetLogger sets the logger of the package. A nil l, the default,
+// disables logging. It is safe to call concurrently.
+func SetLogger(l Logger) {
+	if l == nil {
+		pkgLogger.Store(nil)
+		return
+	}
+	pkgLogger.Store(&l)
+}
+
+// nopLogger is the Logger of the package if SetLogger was not called.
+type nopLogger struct{}
+
+func (nopLogger) Warnf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{}) {}
+
+func logger() Logger {
+	if l := pkgLogger.Load(); l != nil {
+		return *l
+	}
+	return nopLogger{}
+}
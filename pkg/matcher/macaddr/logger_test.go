@@ -0,0 +1,58 @@
+package macaddr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// captureLogger is a Logger that records its messages.
+type captureLogger struct {
+	mu    sync.Mutex
+	warns []string
+	infos []string
+}
+
+func (l *captureLogger) Warnf(template string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, fmt.Sprintf(template, args...))
+}
+
+func (l *captureLogger) Infof(template string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.infos = append(l.infos, fmt.Sprintf(template, args...))
+}
+
+func TestSetLogger(t *testing.T) {
+	l := new(captureLogger)
+	SetLogger(l)
+	defer SetLogger(nil)
+
+	if _, _, err := ParseTextMacFileLenient([]byte("00:11:22:33:44:55\nnot-a-mac\n")); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.warns) != 1 || !strings.Contains(l.warns[0], "line 2") {
+		t.Errorf("warnings = %q, want one for line 2", l.warns)
+	}
+
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	if err := d.Update([]byte("00:11:22:33:44:55\n")); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.infos) != 1 || !strings.Contains(l.infos[0], "1 entries") {
+		t.Errorf("infos = %q, want one update with 1 entries", l.infos)
+	}
+
+	SetLogger(nil)
+	if _, _, err := ParseTextMacFileLenient([]byte("not-a-mac\n")); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.warns) != 1 {
+		t.Errorf("removed logger should not be called, warnings = %q", l.warns)
+	}
+}
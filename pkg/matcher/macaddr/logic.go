@@ -0,0 +1,87 @@
+package macaddr
+
+import (
+	"errors"
+	"net"
+)
+
+// And returns a LocalMatcher that matches a MAC if all of ms match it,
+// evaluated in order until the first miss. With no matchers it matches
+// every MAC. Len is the sum of the lengths of ms, and Close closes all of
+// them. Use it with Not to express e.g. "in list A but not in list B":
+//
+//	And(a, Not(b))
+func And(ms ...LocalMatcher) LocalMatcher {
+	return andMatcher(ms)
+}
+
+// Or returns a LocalMatcher that matches a MAC if any of ms matches it,
+// evaluated in order until the first hit. With no matchers it matches
+// nothing. It is like a LocalMatcherGroup without exclusions.
+// Len is the sum of the lengths of ms, and Close closes all of them.
+func Or(ms ...LocalMatcher) LocalMatcher {
+	return orMatcher(ms)
+}
+
+// Not returns a LocalMatcher that matches a MAC if m does not. The number
+// of entries of a negation is not defined, so its Len is 0. Close closes m.
+func Not(m LocalMatcher) LocalMatcher {
+	return notMatcher{m: m}
+}
+
+type andMatcher []LocalMatcher
+
+func (ms andMatcher) Match(mac net.HardwareAddr) bool {
+	for _, m := range ms {
+		if !m.Match(mac) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ms andMatcher) Len() int     { return sumLen(ms) }
+func (ms andMatcher) Close() error { return closeAll(ms) }
+func (ms andMatcher) Kind() string { return "and" }
+
+type orMatcher []LocalMatcher
+
+func (ms orMatcher) Match(mac net.HardwareAddr) bool {
+	for _, m := range ms {
+		if m.Match(mac) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ms orMatcher) Len() int     { return sumLen(ms) }
+func (ms orMatcher) Close() error { return closeAll(ms) }
+func (ms orMatcher) Kind() string { return "or" }
+
+type notMatcher struct {
+	m LocalMatcher
+}
+
+func (n notMatcher) Match(mac net.HardwareAddr) bool { return !n.m.Match(mac) }
+func (n notMatcher) Len() int                        { return 0 }
+func (n notMatcher) Close() error                    { return n.m.Close() }
+func (n notMatcher) Kind() string                    { return "not" }
+
+func sumLen(ms []LocalMatcher) int {
+	s := 0
+	for _, m := range ms {
+		s += m.Len()
+	}
+	return s
+}
+
+func closeAll(ms []LocalMatcher) error {
+	var errs []error
+	for _, m := range ms {
+		if err := m.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package macaddr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLogicOperators(t *testing.T) {
+	a := newTestMatcher(t, "00:00:00:00:00:01", "00:00:00:00:00:03")
+	b := newTestMatcher(t, "00:00:00:00:00:02", "00:00:00:00:00:03")
+	tests := []struct {
+		mac          string
+		and, or, not bool // a and b, a or b, not a
+		andNot       bool // a and not b
+	}{
+		{"00:00:00:00:00:00", false, false, true, false}, // neither
+		{"00:00:00:00:00:01", false, true, false, true},  // a only
+		{"00:00:00:00:00:02", false, true, true, false},  // b only
+		{"00:00:00:00:00:03", true, true, false, false},  // both
+	}
+	and, or, not, andNot := And(a, b), Or(a, b), Not(a), And(a, Not(b))
+	for _, tt := range tests {
+		mac := mustParseMAC(tt.mac)
+		if got := and.Match(mac); got != tt.and {
+			t.Errorf("And.Match(%s) = %v, want %v", tt.mac, got, tt.and)
+		}
+		if got := or.Match(mac); got != tt.or {
+			t.Errorf("Or.Match(%s) = %v, want %v", tt.mac, got, tt.or)
+		}
+		if got := not.Match(mac); got != tt.not {
+			t.Errorf("Not.Match(%s) = %v, want %v", tt.mac, got, tt.not)
+		}
+		if got := andNot.Match(mac); got != tt.andNot {
+			t.Errorf("And(a, Not(b)).Match(%s) = %v, want %v", tt.mac, got, tt.andNot)
+		}
+	}
+
+	mac := mustParseMAC("00:00:00:00:00:01")
+	if !And().Match(mac) || Or().Match(mac) {
+		t.Error("empty And should match everything and empty Or nothing")
+	}
+	if and.Len() != 4 || or.Len() != 4 || not.Len() != 0 {
+		t.Errorf("Len() = %d, %d, %d, want 4, 4, 0", and.Len(), or.Len(), not.Len())
+	}
+}
+
+func TestLogicOperators_Close(t *testing.T) {
+	var closed []string
+	m := func(name string) LocalMatcher {
+		return &closeRecorder{Matcher: NewMatcher(), closed: &closed, name: name}
+	}
+	if err := Or(And(m("a"), Not(m("b"))), m("c")).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(closed, want) {
+		t.Errorf("closed %v, want %v", closed, want)
+	}
+}
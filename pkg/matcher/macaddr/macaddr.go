@@ -5,34 +5,142 @@ package macaddr
 import (
 	"fmt"
 	"net"
+	"strings"
 )
 
 // Matcher is a MAC address matcher that uses a hash map for efficient lookups.
+// It stores both EUI-48 (6 bytes) and EUI-64 (8 bytes) addresses.
 type Matcher struct {
-	// macs stores the MAC addresses. Using [6]byte as the key for direct comparison.
+	// macs stores the EUI-48 MAC addresses. Using [6]byte as the key for direct comparison.
 	// struct{} is used as the value to minimize memory footprint.
 	macs map[[6]byte]struct{}
+	// macs64 stores the EUI-64 addresses. It is allocated on first use.
+	macs64 map[[8]byte]struct{}
+	// macs20 stores the 20-byte IPoIB addresses. It is only allocated by
+	// NewMatcherIB, other matchers reject such addresses.
+	macs20 map[[20]byte]struct{}
+	// prefixes stores the 3-byte OUI (vendor) prefixes. It is allocated on first use.
+	prefixes map[[3]byte]struct{}
+	// masked stores the EUI-48 patterns with wildcard octets. They are only
+	// scanned if both exact and prefix lookups miss.
+	masked []maskedMAC
+	// ranges stores the inclusive EUI-48 ranges.
+	ranges rangeList
+	// meta stores the optional tags and priorities of EUI-48 entries. It is
+	// allocated on first use, so matchers without meta pay nothing for it.
+	meta map[[6]byte]entryMeta
+	// tagIndex maps each tag to its EUI-48 entries, see MACsForTag. It is
+	// allocated with meta.
+	tagIndex map[string][][6]byte
+	// bloom is an optional prefilter of macs. See NewMatcherBloom.
+	bloom *bloomFilter
+	// strict rejects group addresses in Add. See NewMatcherStrict.
+	strict bool
+	// observer is notified of every Match if not nil. See SetObserver.
+	observer MatchObserver
 }
 
 // Match checks if the given MAC address is in the matcher's set.
 // It returns true if the MAC address is found, false otherwise.
 func (m *Matcher) Match(mac net.HardwareAddr) bool {
-	// Ensure the MAC address is 6 bytes long (EUI-48)
-	if len(mac) != 6 {
+	hit := m.match(mac)
+	if m.observer != nil {
+		m.observer.ObserveMatch(hit)
+	}
+	return hit
+}
+
+func (m *Matcher) match(mac net.HardwareAddr) bool {
+	switch len(mac) {
+	case 6: // EUI-48
+		return m.matchKey([6]byte(mac))
+	case 8: // EUI-64
+		var key [8]byte
+		copy(key[:], mac)
+
+		if len(m.macs64) > 0 {
+			if _, found := m.macs64[key]; found {
+				return true
+			}
+		}
+		return m.matchPrefix(mac)
+	case 20: // IPoIB, see NewMatcherIB
+		_, found := m.macs20[[20]byte(mac)]
+		return found
+	default:
 		return false
 	}
+}
 
-	// Convert []byte to [6]byte for use as a map key
-	var key [6]byte
-	copy(key[:], mac)
+// MatchWithTag is like Match, but also returns the tag of the matched entry.
+// tag is empty if the entry has no tag or mac matched a pattern.
+func (m *Matcher) MatchWithTag(mac net.HardwareAddr) (tag string, ok bool) {
+	tag, _, ok = m.MatchWithMeta(mac)
+	return tag, ok
+}
+
+// MatchWithMeta is like MatchWithTag, but also returns the priority of the
+// matched entry, see AddWithMeta. priority is 0 if the entry has none or
+// mac matched a pattern.
+func (m *Matcher) MatchWithMeta(mac net.HardwareAddr) (tag string, priority int, ok bool) {
+	if len(mac) != 6 {
+		return "", 0, m.Match(mac)
+	}
+	meta, ok := m.matchMeta([6]byte(mac))
+	if m.observer != nil {
+		m.observer.ObserveMatch(ok)
+	}
+	return meta.tag, meta.priority, ok
+}
+
+// matchMeta is like matchKey, but also returns the meta of the matched
+// exact entry. The meta is zero if key matched a pattern.
+func (m *Matcher) matchMeta(key [6]byte) (entryMeta, bool) {
+	if len(m.macs) > 0 && (m.bloom == nil || m.bloom.mayContain(key)) {
+		if _, found := m.macs[key]; found {
+			return m.meta[key], true
+		}
+	}
+	return entryMeta{}, m.matchPattern48(key[:])
+}
+
+// matchPattern48 checks a 6-byte mac against all non-exact patterns.
+func (m *Matcher) matchPattern48(mac net.HardwareAddr) bool {
+	return m.matchPrefix(mac) || m.matchRange(mac) || m.matchMasked(mac)
+}
 
-	_, found := m.macs[key]
+// matchPrefix checks if the OUI of mac is a registered prefix.
+func (m *Matcher) matchPrefix(mac net.HardwareAddr) bool {
+	if len(m.prefixes) == 0 {
+		return false
+	}
+	var key [3]byte
+	copy(key[:], mac)
+	_, found := m.prefixes[key]
 	return found
 }
 
-// Len returns the number of MAC addresses in the matcher.
+// matchRange checks mac against the ranges using a binary search.
+func (m *Matcher) matchRange(mac net.HardwareAddr) bool {
+	if m.ranges.len() == 0 {
+		return false
+	}
+	return m.ranges.contains(mac48ToUint64(mac))
+}
+
+// matchMasked checks mac against the wildcard patterns.
+func (m *Matcher) matchMasked(mac net.HardwareAddr) bool {
+	for _, p := range m.masked {
+		if p.match(mac) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of MAC addresses and patterns in the matcher.
 func (m *Matcher) Len() int {
-	return len(m.macs)
+	return len(m.macs) + len(m.macs64) + len(m.macs20) + len(m.prefixes) + len(m.masked) + m.ranges.len()
 }
 
 // Close implements the io.Closer interface.
@@ -43,19 +151,140 @@ func (m *Matcher) Close() error {
 }
 
 // Add adds a MAC address pattern to the matcher.
-// pattern should be a valid MAC address string, e.g., "aa:bb:cc:dd:ee:ff".
+// pattern should be a valid EUI-48 or EUI-64 address string,
+// e.g., "aa:bb:cc:dd:ee:ff" or "aa:bb:cc:dd:ee:ff:00:11",
+// or a 3-octet OUI prefix, e.g., "aa:bb:cc" or "AA-BB-CC", which matches
+// all addresses of that vendor,
+// or an EUI-48 pattern with "*" as wildcard octets, e.g., "aa:bb:*:dd:*:*",
+// or an inclusive EUI-48 range, e.g., "00:11:22:33:44:00-00:11:22:33:44:ff".
 func (m *Matcher) Add(pattern string, v struct{}) error {
-	hwAddr, err := net.ParseMAC(pattern)
+	_, _, err := m.addPattern(pattern)
+	return err
+}
+
+// AddWithTag is like Add, but associates tag with the entry, which is
+// returned by MatchWithTag. Tags are only kept for EUI-48 addresses,
+// other patterns are added without tag. Adding an existing address again
+// replaces its tag, and clears its priority, see AddWithMeta.
+func (m *Matcher) AddWithTag(pattern, tag string) error {
+	return m.AddWithMeta(pattern, tag, 0)
+}
+
+// AddWithMeta is like AddWithTag, but also associates priority with the
+// entry, e.g. for routing decisions by device group. It is returned by
+// MatchWithMeta. Adding an existing address again replaces its tag and
+// priority.
+func (m *Matcher) AddWithMeta(pattern, tag string, priority int) error {
+	key, exact, err := m.addPattern(pattern)
+	if err != nil {
+		return err
+	}
+	if exact {
+		m.setMeta(key, entryMeta{tag: tag, priority: priority})
+	}
+	return nil
+}
+
+// AddMany adds patterns to the matcher. Blank patterns are skipped.
+// It stops at the first invalid pattern and returns an error that tells
+// its index. Patterns before it are kept.
+func (m *Matcher) AddMany(patterns ...string) error {
+	for i, p := range patterns {
+		p = strings.TrimSpace(p)
+		if len(p) == 0 {
+			continue
+		}
+		if _, _, err := m.addPattern(p); err != nil {
+			return fmt.Errorf("pattern at index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// addPattern adds pattern to the matcher. If pattern is an EUI-48 address,
+// it returns its key and exact is true.
+func (m *Matcher) addPattern(pattern string) (key [6]byte, exact bool, err error) {
+	p, err := parsePattern(pattern)
+	if err != nil {
+		return key, false, err
+	}
+	switch p.kind {
+	case KindRange:
+		m.ranges.add(p.r)
+	case KindWildcard:
+		m.masked = append(m.masked, p.masked)
+	case KindPrefix:
+		m.addPrefix(p.oui)
+	case KindExact:
+		if !m.validLen(len(p.mac)) {
+			return key, false, m.lenError(len(p.mac))
+		}
+		if err := m.checkStrict(p.mac); err != nil {
+			return key, false, err
+		}
+		m.add(p.mac)
+		if len(p.mac) == 6 {
+			return [6]byte(p.mac), true, nil
+		}
+	}
+	return key, false, nil
+}
+
+// entryMeta is the optional tag and priority of an EUI-48 entry.
+type entryMeta struct {
+	tag      string
+	priority int
+}
+
+// setMeta sets the meta of an EUI-48 entry. The zero meta clears it.
+func (m *Matcher) setMeta(key [6]byte, meta entryMeta) {
+	if old, ok := m.meta[key]; ok && len(old.tag) > 0 {
+		m.unindexTag(old.tag, key)
+	}
+	if meta == (entryMeta{}) {
+		delete(m.meta, key)
+		return
+	}
+	if m.meta == nil {
+		m.meta = make(map[[6]byte]entryMeta)
+		m.tagIndex = make(map[string][][6]byte)
+	}
+	m.meta[key] = meta
+	if len(meta.tag) > 0 {
+		m.tagIndex[meta.tag] = append(m.tagIndex[meta.tag], key)
+	}
+}
+
+// Remove removes a MAC address or OUI prefix pattern from the matcher.
+// It returns an error if pattern is invalid, but not if it was not present.
+// Matcher is not synchronized, callers that modify a Matcher while it is
+// being matched must guard it, e.g. by using SyncMatcher.
+func (m *Matcher) Remove(pattern string) error {
+	switch kind := classify(pattern); kind {
+	case KindPrefix:
+		p, err := parsePartialMAC(pattern)
+		if err != nil {
+			return err
+		}
+		delete(m.prefixes, [3]byte(p))
+		return nil
+	case KindRange, KindWildcard:
+		return fmt.Errorf("cannot remove %s pattern %s, only addresses and prefixes", kind, pattern)
+	}
+	hwAddr, err := parseMAC(pattern)
 	if err != nil {
 		return fmt.Errorf("invalid MAC address %s: %w", pattern, err)
 	}
-	if len(hwAddr) != 6 {
-		return fmt.Errorf("MAC address must be 6 bytes, got %d", len(hwAddr))
+	if !m.validLen(len(hwAddr)) {
+		return m.lenError(len(hwAddr))
 	}
-	m.add(hwAddr)
+	m.remove(hwAddr)
 	return nil
 }
 
+// Kind returns "static", see LocalMatcher.
+func (m *Matcher) Kind() string { return "static" }
+
 // NewMatcher creates a new empty Matcher.
 func NewMatcher() *Matcher {
 	return &Matcher{
@@ -63,12 +292,67 @@ func NewMatcher() *Matcher {
 	}
 }
 
+// NewMatcherSize is like NewMatcher, but sizes the EUI-48 map for hint
+// entries, which avoids rehashing while loading a list of known size.
+func NewMatcherSize(hint int) *Matcher {
+	return &Matcher{
+		macs: make(map[[6]byte]struct{}, max(hint, 0)),
+	}
+}
+
 // add is a helper method to add a net.HardwareAddr to the internal map.
-// It assumes the MAC address is valid and 6 bytes long.
+// Callers must validate mac first. As a guard, addresses of an invalid
+// length are ignored instead of being truncated or zero padded.
 func (m *Matcher) add(mac net.HardwareAddr) {
+	if !m.validLen(len(mac)) {
+		return
+	}
+	if len(mac) == 20 {
+		m.macs20[[20]byte(mac)] = struct{}{}
+		return
+	}
+	if len(mac) == 8 {
+		if m.macs64 == nil {
+			m.macs64 = make(map[[8]byte]struct{})
+		}
+		var key [8]byte
+		copy(key[:], mac)
+		m.macs64[key] = struct{}{}
+		return
+	}
 	var key [6]byte
 	copy(key[:], mac)
 	m.macs[key] = struct{}{}
+	if m.bloom != nil {
+		m.bloom.add(key)
+	}
+}
+
+// remove is a helper method to delete a net.HardwareAddr from the internal map.
+// It assumes the MAC address has a valid length, see validLen.
+func (m *Matcher) remove(mac net.HardwareAddr) {
+	if len(mac) == 20 {
+		delete(m.macs20, [20]byte(mac))
+		return
+	}
+	if len(mac) == 8 {
+		var key [8]byte
+		copy(key[:], mac)
+		delete(m.macs64, key)
+		return
+	}
+	var key [6]byte
+	copy(key[:], mac)
+	delete(m.macs, key)
+	m.setMeta(key, entryMeta{})
+}
+
+// addPrefix adds an OUI prefix to the internal prefix map.
+func (m *Matcher) addPrefix(oui [3]byte) {
+	if m.prefixes == nil {
+		m.prefixes = make(map[[3]byte]struct{})
+	}
+	m.prefixes[oui] = struct{}{}
 }
 
 // Ensure that Matcher implements the necessary interface for msg_matcher.NewMacAddressMatcher
@@ -2,6 +2,7 @@ package macaddr
 
 import (
 	"net"
+	"strings"
 	"testing"
 )
 
@@ -164,3 +165,330 @@ func TestMatcher_CaseInsensitive(t *testing.T) {
 		t.Errorf("Expected length 1, got %d", m.Len())
 	}
 }
+
+func TestMatcher_EUI64(t *testing.T) {
+	m := NewMatcher()
+	for _, s := range []string{"00:11:22:33:44:55", "00:11:22:33:44:55:66:77", "00-11-22-33-44-55-66-88"} {
+		if err := m.Add(s, struct{}{}); err != nil {
+			t.Fatalf("Add(%s): %v", s, err)
+		}
+	}
+
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, expected 3", m.Len())
+	}
+
+	tests := []struct {
+		mac      string
+		expected bool
+	}{
+		{"00:11:22:33:44:55", true},
+		{"00:11:22:33:44:55:66:77", true},
+		{"00:11:22:33:44:55:66:88", true},
+		{"00:11:22:33:44:55:66:99", false},
+		{"00:11:22:33:44:66", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(mustParseMAC(tt.mac)); got != tt.expected {
+			t.Errorf("Match(%s) = %v, expected %v", tt.mac, got, tt.expected)
+		}
+	}
+
+	// A 6-byte query must not match the leading bytes of an EUI-64 entry.
+	if m.Match(net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x77}) {
+		t.Error("EUI-48 query should not match EUI-64 entries")
+	}
+}
+
+func TestMatcher_OUIPrefix(t *testing.T) {
+	m := NewMatcher()
+	const data = `
+# vendor
+ac:de:48
+00-1B-A9
+00:11:22:33:44:55
+`
+	if err := LoadFromTextReader(m, strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, expected 3", m.Len())
+	}
+
+	tests := []struct {
+		mac      string
+		expected bool
+	}{
+		{"ac:de:48:00:00:01", true},
+		{"ac:de:48:ff:ff:ff", true},
+		{"00:1b:a9:12:34:56", true},
+		{"00:1b:a9:12:34:56:78:90", true},
+		{"00:11:22:33:44:55", true},
+		{"00:11:22:33:44:56", false},
+		{"ac:de:49:00:00:01", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(mustParseMAC(tt.mac)); got != tt.expected {
+			t.Errorf("Match(%s) = %v, expected %v", tt.mac, got, tt.expected)
+		}
+	}
+}
+
+func TestMatcher_Remove(t *testing.T) {
+	m := NewMatcher()
+	macs := []string{"00:11:22:33:44:55", "66:77:88:99:aa:bb", "cc:dd:ee:ff:00:11"}
+	for _, s := range macs {
+		if err := m.Add(s, struct{}{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.Remove("66-77-88-99-AA-BB"); err != nil {
+		t.Fatalf("Remove() returned error: %v", err)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, expected 2", m.Len())
+	}
+	if m.Match(mustParseMAC(macs[1])) {
+		t.Error("removed MAC address should not match")
+	}
+	for _, s := range []string{macs[0], macs[2]} {
+		if !m.Match(mustParseMAC(s)) {
+			t.Errorf("%s should still match", s)
+		}
+	}
+
+	// Removing an absent MAC is not an error, an invalid pattern is.
+	if err := m.Remove("01:02:03:04:05:06"); err != nil {
+		t.Errorf("Remove() of absent MAC returned error: %v", err)
+	}
+	if err := m.Remove("invalid"); err == nil {
+		t.Error("Remove() of invalid pattern should fail")
+	}
+}
+
+func TestMatcher_MatchWithTag(t *testing.T) {
+	m := NewMatcher()
+	entries := []struct{ mac, tag string }{
+		{"00:11:22:33:44:55", "laptop"},
+		{"66:77:88:99:aa:bb", "phone"},
+		{"cc:dd:ee:ff:00:11", ""},
+		{"ac:de:48", "vendor"},
+	}
+	for _, e := range entries {
+		if err := m.AddWithTag(e.mac, e.tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		mac     string
+		wantTag string
+		wantOk  bool
+	}{
+		{"00:11:22:33:44:55", "laptop", true},
+		{"66:77:88:99:aa:bb", "phone", true},
+		{"cc:dd:ee:ff:00:11", "", true},
+		{"ac:de:48:00:00:01", "", true}, // patterns are untagged
+		{"01:02:03:04:05:06", "", false},
+	}
+	for _, tt := range tests {
+		tag, ok := m.MatchWithTag(mustParseMAC(tt.mac))
+		if tag != tt.wantTag || ok != tt.wantOk {
+			t.Errorf("MatchWithTag(%s) = (%q, %v), expected (%q, %v)", tt.mac, tag, ok, tt.wantTag, tt.wantOk)
+		}
+	}
+
+	if err := m.Remove("00:11:22:33:44:55"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("00:11:22:33:44:55", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if tag, _ := m.MatchWithTag(mustParseMAC("00:11:22:33:44:55")); tag != "" {
+		t.Errorf("tag should be removed with its entry, got %q", tag)
+	}
+}
+
+func TestMatcher_AddLength(t *testing.T) {
+	const ib = "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"
+	if mac, err := net.ParseMAC(ib); err != nil || len(mac) != 20 {
+		t.Fatalf("ParseMAC(%s) = %v, %v, want a 20-byte address", ib, mac, err)
+	}
+
+	m := NewMatcher()
+	err := m.Add(ib, struct{}{})
+	if err == nil || !strings.Contains(err.Error(), "got 20") {
+		t.Errorf("Add() error = %v, want a length error", err)
+	}
+
+	m.add(net.HardwareAddr{0x00, 0x11, 0x22})
+	m.add(mustParseMAC(ib))
+	if m.Len() != 0 {
+		t.Errorf("add() should ignore invalid lengths, Len() = %d", m.Len())
+	}
+}
+
+func TestMatcher_MatchWithMeta(t *testing.T) {
+	m := NewMatcher()
+	if err := m.AddWithMeta("00:11:22:33:44:55", "kids", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddWithMeta("66:77:88:99:aa:bb", "", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("cc:dd:ee:ff:00:11", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddWithMeta("ac:de:48", "vendor", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mac          string
+		wantTag      string
+		wantPriority int
+		wantOk       bool
+	}{
+		{"00:11:22:33:44:55", "kids", 10, true},
+		{"66:77:88:99:aa:bb", "", 5, true},
+		{"cc:dd:ee:ff:00:11", "", 0, true},
+		{"ac:de:48:00:00:01", "", 0, true}, // patterns have no meta
+		{"01:02:03:04:05:06", "", 0, false},
+	}
+	for _, tt := range tests {
+		tag, priority, ok := m.MatchWithMeta(mustParseMAC(tt.mac))
+		if tag != tt.wantTag || priority != tt.wantPriority || ok != tt.wantOk {
+			t.Errorf("MatchWithMeta(%s) = (%q, %d, %v), want (%q, %d, %v)",
+				tt.mac, tag, priority, ok, tt.wantTag, tt.wantPriority, tt.wantOk)
+		}
+	}
+
+	// Adding again overwrites the meta, AddWithTag clears the priority.
+	mac := mustParseMAC("00:11:22:33:44:55")
+	if err := m.AddWithMeta("00:11:22:33:44:55", "guests", 3); err != nil {
+		t.Fatal(err)
+	}
+	if tag, priority, _ := m.MatchWithMeta(mac); tag != "guests" || priority != 3 {
+		t.Errorf("MatchWithMeta() = (%q, %d), want (\"guests\", 3)", tag, priority)
+	}
+	if err := m.AddWithTag("00:11:22:33:44:55", "kids"); err != nil {
+		t.Fatal(err)
+	}
+	if tag, priority, _ := m.MatchWithMeta(mac); tag != "kids" || priority != 0 {
+		t.Errorf("MatchWithMeta() = (%q, %d), want (\"kids\", 0)", tag, priority)
+	}
+	if got := m.MACsForTag("guests"); len(got) != 0 {
+		t.Errorf("MACsForTag(guests) = %v, want none", got)
+	}
+}
+
+func TestMatcher_LengthErrorWording(t *testing.T) {
+	const ib = "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"
+	const eui64 = "aa:bb:cc:dd:ee:ff:00:11"
+	var dst [6]byte
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"ib to default matcher", NewMatcher().Add(ib, struct{}{}),
+			"expected 6-octet EUI-48 or 8-octet EUI-64, got 20-octet address"},
+		{"remove ib from default matcher", NewMatcher().Remove(ib),
+			"expected 6-octet EUI-48 or 8-octet EUI-64, got 20-octet address"},
+		{"eui-64 range", NewMatcher().Add(eui64+"-aa:bb:cc:dd:ee:ff:00:ff", struct{}{}),
+			"expected 6-octet EUI-48, got 8-octet address"},
+		{"eui-64 wildcard", NewMatcher().Add("aa:*:cc:dd:ee:ff:00:11", struct{}{}),
+			"expected 6-octet EUI-48, got 8-octet address"},
+		{"eui-64 ParseMACInto", ParseMACInto(&dst, eui64),
+			"expected 6-octet EUI-48, got 8-octet address"},
+	}
+	for _, tt := range tests {
+		if tt.err == nil || !strings.HasSuffix(tt.err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want one ending with %q", tt.name, tt.err, tt.want)
+		}
+	}
+}
+
+func TestMatcher_AddMany(t *testing.T) {
+	m := NewMatcher()
+	if err := m.AddMany("00:11:22:33:44:55", " ", "aa:bb:cc", "00:11:22:33:44:00-00:11:22:33:44:0f"); err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+
+	m = NewMatcher()
+	err := m.AddMany("00:11:22:33:44:55", "00:11:22:33:44:66", "bad", "aa:bb:cc:dd:ee:ff")
+	if err == nil || !strings.Contains(err.Error(), "index 2") {
+		t.Errorf("AddMany() error = %v, want an error at index 2", err)
+	}
+	if m.Len() != 2 {
+		t.Errorf("entries before the bad one should be kept, Len() = %d", m.Len())
+	}
+}
+
+func TestCountDataLines(t *testing.T) {
+	in := []byte("# header\n00:11:22:33:44:55\n\n  aa:bb:cc # vendor\n  # indented comment\n00:11:22:33:44:66")
+	if got := countDataLines(in); got != 3 {
+		t.Errorf("countDataLines() = %d, want 3", got)
+	}
+}
+
+// benchmarkLoad100k inserts parsed addresses, because parsing dominates
+// Add and would hide the cost of growing the map.
+func benchmarkLoad100k(b *testing.B, newMatcher func(n int) *Matcher) {
+	const n = 100_000
+	macs := make([]net.HardwareAddr, n)
+	for i := range macs {
+		macs[i] = net.HardwareAddr{0x02, 0, 0, byte(i >> 16), byte(i >> 8), byte(i)}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := newMatcher(n)
+		for _, mac := range macs {
+			m.add(mac)
+		}
+	}
+}
+
+func BenchmarkLoad100k_NoHint(b *testing.B) {
+	benchmarkLoad100k(b, func(int) *Matcher { return NewMatcher() })
+}
+
+func BenchmarkLoad100k_SizeHint(b *testing.B) {
+	benchmarkLoad100k(b, NewMatcherSize)
+}
+
+// benchmarkPrefixOnly matches a list of 1000 OUI prefixes, plus the exact
+// entries of exact, with half of the lookups hitting a prefix.
+func benchmarkPrefixOnly(b *testing.B, exact ...string) {
+	m := NewMatcher()
+	for i := 0; i < 1000; i++ {
+		m.addPrefix([3]byte{byte(i >> 8), byte(i), 0x01})
+	}
+	if err := m.AddMany(exact...); err != nil {
+		b.Fatal(err)
+	}
+	macs := make([][]byte, 2000)
+	for i := range macs {
+		macs[i] = []byte{byte(i >> 8), byte(i), byte(i%2 + 1), 0x00, 0x00, 0x01}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Match(macs[i%len(macs)])
+	}
+}
+
+func BenchmarkMatcher_MatchPrefixOnly(b *testing.B) {
+	benchmarkPrefixOnly(b)
+}
+
+// BenchmarkMatcher_MatchPrefixWithExact is the baseline of
+// BenchmarkMatcher_MatchPrefixOnly, a single exact entry forces the exact
+// map lookup before the prefix check.
+func BenchmarkMatcher_MatchPrefixWithExact(b *testing.B) {
+	benchmarkPrefixOnly(b, "ff:ff:ff:00:00:01")
+}
@@ -0,0 +1,78 @@
+// Package macaddrprom exports the metrics of macaddr matchers to
+// prometheus. It is a separate package, so users of macaddr that do not
+// export metrics do not depend on prometheus.
+package macaddrprom
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pmkol/mosdns-x/pkg/matcher/macaddr"
+)
+
+// observable is a matcher that notifies an observer of its matches, e.g.
+// macaddr.Matcher and macaddr.DynamicMatcher.
+type observable interface {
+	SetObserver(o macaddr.MatchObserver)
+}
+
+// collector is the prometheus.Collector of a matcher.
+type collector struct {
+	entriesDesc *prometheus.Desc
+	hitsDesc    *prometheus.Desc
+	m           macaddr.LocalMatcher
+	observed    bool
+	hits        atomic.Uint64
+}
+
+// NewCollector returns a prometheus.Collector that reports the entry count
+// of m as mosdns_macaddr_entries{matcher=name}, which calls m.Len on each
+// scrape. If m notifies an observer of its matches, e.g. a *macaddr.Matcher,
+// the collector becomes its observer and also reports the hit count as
+// mosdns_macaddr_match_hits_total{matcher=name}. This replaces a previous
+// observer of m.
+func NewCollector(name string, m macaddr.LocalMatcher) prometheus.Collector {
+	labels := prometheus.Labels{"matcher": name}
+	c := &collector{
+		entriesDesc: prometheus.NewDesc(
+			"mosdns_macaddr_entries",
+			"The number of entries of the matcher",
+			nil, labels,
+		),
+		hitsDesc: prometheus.NewDesc(
+			"mosdns_macaddr_match_hits_total",
+			"The number of matches that hit the matcher",
+			nil, labels,
+		),
+		m: m,
+	}
+	if o, ok := m.(observable); ok {
+		o.SetObserver(c)
+		c.observed = true
+	}
+	return c
+}
+
+// ObserveMatch implements macaddr.MatchObserver.
+func (c *collector) ObserveMatch(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entriesDesc
+	if c.observed {
+		ch <- c.hitsDesc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.GaugeValue, float64(c.m.Len()))
+	if c.observed {
+		ch <- prometheus.MustNewConstMetric(c.hitsDesc, prometheus.CounterValue, float64(c.hits.Load()))
+	}
+}
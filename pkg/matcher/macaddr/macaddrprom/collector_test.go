@@ -0,0 +1,82 @@
+package macaddrprom
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/pmkol/mosdns-x/pkg/matcher/macaddr"
+)
+
+func TestCollector(t *testing.T) {
+	m := macaddr.NewMatcher()
+	if err := m.AddMany("00:11:22:33:44:55", "00:11:22:33:44:66", "aa:bb:cc"); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCollector("lan", m)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	hit, _ := net.ParseMAC("00:11:22:33:44:55")
+	miss, _ := net.ParseMAC("00:11:22:33:44:77")
+	m.Match(hit)
+	m.Match(hit)
+	m.Match(miss)
+	if err := m.Add("00:11:22:33:44:88", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := fmt.Sprintf(`
+# HELP mosdns_macaddr_entries The number of entries of the matcher
+# TYPE mosdns_macaddr_entries gauge
+mosdns_macaddr_entries{matcher="lan"} %d
+# HELP mosdns_macaddr_match_hits_total The number of matches that hit the matcher
+# TYPE mosdns_macaddr_match_hits_total counter
+mosdns_macaddr_match_hits_total{matcher="lan"} 2
+`, m.Len())
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+	if got := testutil.ToFloat64(NewCollector("none", macaddr.MatchNone{})); got != 0 {
+		t.Errorf("entries of MatchNone = %v, want 0", got)
+	}
+}
+
+func TestCollector_SeveralMatchers(t *testing.T) {
+	lan := macaddr.NewMatcher()
+	if err := lan.AddMany("00:11:22:33:44:55", "00:11:22:33:44:66"); err != nil {
+		t.Fatal(err)
+	}
+	wifi := macaddr.NewMatcher()
+	if err := wifi.Add("aa:bb:cc:dd:ee:ff", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(NewCollector("lan", lan)); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(NewCollector("wifi", wifi)); err != nil {
+		t.Fatalf("register second collector: %v", err)
+	}
+
+	hit, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	wifi.Match(hit)
+
+	want := `
+# HELP mosdns_macaddr_entries The number of entries of the matcher
+# TYPE mosdns_macaddr_entries gauge
+mosdns_macaddr_entries{matcher="lan"} 2
+mosdns_macaddr_entries{matcher="wifi"} 1
+# HELP mosdns_macaddr_match_hits_total The number of matches that hit the matcher
+# TYPE mosdns_macaddr_match_hits_total counter
+mosdns_macaddr_match_hits_total{matcher="lan"} 0
+mosdns_macaddr_match_hits_total{matcher="wifi"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,52 @@
+package macaddr
+
+import (
+	"context"
+	"net"
+)
+
+// matchCtxInterval is the number of wildcard patterns MatchContext scans
+// between two checks of the context.
+const matchCtxInterval = 256
+
+// MatchContext is like Match, but abandons the linear scan of the wildcard
+// patterns once ctx is done and returns ctx.Err(). Exact entries, prefixes
+// and ranges are looked up without the scan, so this only matters for
+// matchers with many wildcard patterns. The observer is not notified of
+// abandoned matches.
+func (m *Matcher) MatchContext(ctx context.Context, mac net.HardwareAddr) (bool, error) {
+	if len(mac) != 6 || len(m.masked) < matchCtxInterval {
+		return m.Match(mac), nil
+	}
+	hit, err := m.matchContext(ctx, mac)
+	if err != nil {
+		return false, err
+	}
+	if m.observer != nil {
+		m.observer.ObserveMatch(hit)
+	}
+	return hit, nil
+}
+
+func (m *Matcher) matchContext(ctx context.Context, mac net.HardwareAddr) (bool, error) {
+	key := [6]byte(mac)
+	if m.bloom == nil || m.bloom.mayContain(key) {
+		if _, found := m.macs[key]; found {
+			return true, nil
+		}
+	}
+	if m.matchPrefix(mac) || m.matchRange(mac) {
+		return true, nil
+	}
+	for i, p := range m.masked {
+		if i%matchCtxInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+		}
+		if p.match(mac) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
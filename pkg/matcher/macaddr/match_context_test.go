@@ -0,0 +1,50 @@
+package macaddr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// cancelAfterCtx is a context that is canceled after n calls of Err.
+type cancelAfterCtx struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfterCtx) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestMatcher_MatchContext(t *testing.T) {
+	m := NewMatcher()
+	for i := 0; i < 4096; i++ {
+		if err := m.Add(fmt.Sprintf("aa:bb:%02x:%02x:*:*", i>>8, i&0xff), struct{}{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Add("00:11:22:33:44:55", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if hit, err := m.MatchContext(context.Background(), mustParseMAC("aa:bb:0f:ff:01:02")); !hit || err != nil {
+		t.Errorf("MatchContext() = %v, %v, want true, nil", hit, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if hit, err := m.MatchContext(ctx, mustParseMAC("00:11:22:33:44:55")); !hit || err != nil {
+		t.Errorf("exact MatchContext() = %v, %v, want true, nil without checking ctx", hit, err)
+	}
+
+	// The context passes the first check, and is canceled mid-scan.
+	ctx = &cancelAfterCtx{Context: context.Background(), n: 2}
+	if hit, err := m.MatchContext(ctx, mustParseMAC("ff:ff:ff:ff:ff:00")); hit || !errors.Is(err, context.Canceled) {
+		t.Errorf("MatchContext() = %v, %v, want false, context.Canceled", hit, err)
+	}
+}
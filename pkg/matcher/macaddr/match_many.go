@@ -0,0 +1,54 @@
+package macaddr
+
+import "net"
+
+// matchAny reports whether m matches any of macs. It stops at the first hit.
+func matchAny(m LocalMatcher, macs []net.HardwareAddr) bool {
+	for _, mac := range macs {
+		if m.Match(mac) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAll reports whether m matches all of macs. It stops at the first miss.
+func matchAll(m LocalMatcher, macs []net.HardwareAddr) bool {
+	for _, mac := range macs {
+		if !m.Match(mac) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchAny reports whether any of macs matches, e.g. the MACs of the
+// interfaces of a client. It is false if macs is empty.
+func (m *Matcher) MatchAny(macs []net.HardwareAddr) bool {
+	return matchAny(m, macs)
+}
+
+// MatchAll reports whether all of macs match. It is true if macs is empty.
+func (m *Matcher) MatchAll(macs []net.HardwareAddr) bool {
+	return matchAll(m, macs)
+}
+
+// MatchAny is like Matcher.MatchAny.
+func (mg *LocalMatcherGroup) MatchAny(macs []net.HardwareAddr) bool {
+	return matchAny(mg, macs)
+}
+
+// MatchAll is like Matcher.MatchAll.
+func (mg *LocalMatcherGroup) MatchAll(macs []net.HardwareAddr) bool {
+	return matchAll(mg, macs)
+}
+
+// MatchAny is like Matcher.MatchAny.
+func (d *DynamicMatcher) MatchAny(macs []net.HardwareAddr) bool {
+	return matchAny(d, macs)
+}
+
+// MatchAll is like Matcher.MatchAll.
+func (d *DynamicMatcher) MatchAll(macs []net.HardwareAddr) bool {
+	return matchAll(d, macs)
+}
@@ -0,0 +1,50 @@
+package macaddr
+
+import (
+	"net"
+	"testing"
+)
+
+func TestMatchAnyAll(t *testing.T) {
+	m := newTestMatcher(t, "00:11:22:33:44:01", "00:11:22:33:44:02")
+	mg := &LocalMatcherGroup{}
+	mg.Append(m)
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	if err := d.Update([]byte("00:11:22:33:44:01\n00:11:22:33:44:02\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	in1, in2 := mustParseMAC("00:11:22:33:44:01"), mustParseMAC("00:11:22:33:44:02")
+	out := mustParseMAC("00:11:22:33:44:03")
+	matchers := map[string]interface {
+		MatchAny(macs []net.HardwareAddr) bool
+		MatchAll(macs []net.HardwareAddr) bool
+	}{
+		"Matcher":           m,
+		"LocalMatcherGroup": mg,
+		"DynamicMatcher":    d,
+	}
+	tests := []struct {
+		name    string
+		macs    []net.HardwareAddr
+		wantAny bool
+		wantAll bool
+	}{
+		{"empty", nil, false, true},
+		{"all in", []net.HardwareAddr{in1, in2}, true, true},
+		{"mixed", []net.HardwareAddr{out, in2}, true, false},
+		{"all out", []net.HardwareAddr{out, out}, false, false},
+	}
+	for name, ms := range matchers {
+		for _, tt := range tests {
+			if got := ms.MatchAny(tt.macs); got != tt.wantAny {
+				t.Errorf("%s: MatchAny(%s) = %v, want %v", name, tt.name, got, tt.wantAny)
+			}
+			if got := ms.MatchAll(tt.macs); got != tt.wantAll {
+				t.Errorf("%s: MatchAll(%s) = %v, want %v", name, tt.name, got, tt.wantAll)
+			}
+		}
+	}
+}
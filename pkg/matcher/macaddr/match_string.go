@@ -0,0 +1,33 @@
+package macaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// matchString parses s as a MAC address and matches it with m. Like the
+// entries of a mac list, s may be 12 hex digits without separators.
+// Surrounding whitespace is ignored.
+func matchString(m LocalMatcher, s string) (bool, error) {
+	s = strings.TrimSpace(s)
+	mac, err := parseMAC(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid MAC address %s: %w", s, err)
+	}
+	return m.Match(mac), nil
+}
+
+// MatchString is like Match, but parses the MAC address from s.
+func (m *Matcher) MatchString(s string) (bool, error) {
+	return matchString(m, s)
+}
+
+// MatchString is like Match, but parses the MAC address from s.
+func (mg *LocalMatcherGroup) MatchString(s string) (bool, error) {
+	return matchString(mg, s)
+}
+
+// MatchString is like Match, but parses the MAC address from s.
+func (d *DynamicMatcher) MatchString(s string) (bool, error) {
+	return matchString(d, s)
+}
@@ -0,0 +1,47 @@
+package macaddr
+
+import "testing"
+
+func TestMatchString(t *testing.T) {
+	m := newTestMatcher(t, "00:11:22:33:44:55")
+	mg := &LocalMatcherGroup{}
+	mg.Append(m)
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	if err := d.Update([]byte("00:11:22:33:44:55\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	matchers := map[string]interface {
+		MatchString(s string) (bool, error)
+	}{
+		"Matcher":           m,
+		"LocalMatcherGroup": mg,
+		"DynamicMatcher":    d,
+	}
+	tests := []struct {
+		name    string
+		s       string
+		want    bool
+		wantErr bool
+	}{
+		{"match", " 00:11:22:33:44:55\n", true, false},
+		{"bare", "001122334455", true, false},
+		{"miss", "00:11:22:33:44:66", false, false},
+		{"malformed", "00:11:22:33:44", false, true},
+	}
+	for name, ms := range matchers {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				got, err := ms.MatchString(tt.s)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("MatchString() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("MatchString() = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
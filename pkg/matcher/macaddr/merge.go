@@ -0,0 +1,78 @@
+package macaddr
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+)
+
+// Entries returns the EUI-48 addresses of the matcher in ascending order.
+// Other entries, e.g. EUI-64 addresses or patterns, are not included.
+func (m *Matcher) Entries() [][6]byte {
+	keys := make([][6]byte, 0, len(m.macs))
+	for k := range m.macs {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b [6]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return keys
+}
+
+// entriesMatcher is a LocalMatcher that exposes its entries.
+type entriesMatcher interface {
+	LocalMatcher
+	Entries() [][6]byte
+}
+
+// MergeMatchers unions the entries of ms into a new Matcher, which matches
+// without the per-matcher loop of a LocalMatcherGroup. Every matcher must
+// implement Entries and hold only entries that Entries returns, otherwise
+// an error is returned instead of silently dropping entries.
+func MergeMatchers(ms ...LocalMatcher) (*Matcher, error) {
+	merged := NewMatcher()
+	for i, lm := range ms {
+		em, ok := lm.(entriesMatcher)
+		if !ok {
+			return nil, fmt.Errorf("matcher #%d (%T) does not support Entries", i, lm)
+		}
+		entries := em.Entries()
+		if n := em.Len() - len(entries); n > 0 {
+			return nil, fmt.Errorf("matcher #%d has %d entries that are not EUI-48 addresses", i, n)
+		}
+		for _, k := range entries {
+			merged.add(k[:])
+		}
+	}
+	return merged, nil
+}
+
+// addFrom adds all entries of o, with their tags and priorities, to m.
+// It returns false without adding anything if m cannot take all of them,
+// i.e. if o holds IPoIB addresses and m does not accept them, or if m is
+// strict and o is not.
+func (m *Matcher) addFrom(o *Matcher) bool {
+	if (len(o.macs20) > 0 && m.macs20 == nil) || (m.strict && !o.strict) {
+		return false
+	}
+	for k := range o.macs {
+		m.add(k[:])
+	}
+	for k, meta := range o.meta {
+		m.setMeta(k, meta)
+	}
+	for k := range o.macs64 {
+		m.add(k[:])
+	}
+	for k := range o.macs20 {
+		m.add(k[:])
+	}
+	for oui := range o.prefixes {
+		m.addPrefix(oui)
+	}
+	m.masked = append(m.masked, o.masked...)
+	for _, r := range o.ranges.ranges {
+		m.ranges.add(r)
+	}
+	return true
+}
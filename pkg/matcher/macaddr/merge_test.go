@@ -0,0 +1,28 @@
+package macaddr
+
+import "testing"
+
+func TestMergeMatchers(t *testing.T) {
+	a := newTestMatcher(t, "00:11:22:33:44:55", "00:11:22:33:44:66")
+	b := newTestMatcher(t, "00:11:22:33:44:66", "aa:bb:cc:dd:ee:ff")
+
+	m, err := MergeMatchers(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+	for _, s := range []string{"00:11:22:33:44:55", "00:11:22:33:44:66", "aa:bb:cc:dd:ee:ff"} {
+		if !m.Match(mustParseMAC(s)) {
+			t.Errorf("merged matcher should match %s", s)
+		}
+	}
+
+	if _, err := MergeMatchers(a, &LocalMatcherGroup{}); err == nil {
+		t.Error("expected error for matcher without Entries")
+	}
+	if _, err := MergeMatchers(newTestMatcher(t, "aa:bb:cc")); err == nil {
+		t.Error("expected error for matcher with a prefix entry")
+	}
+}
@@ -0,0 +1,67 @@
+package macaddr
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// ErrNeighborNotFound is returned by ResolveClientMAC if the IP is not in
+// the neighbor table.
+var ErrNeighborNotFound = errors.New("ip not found in neighbor table")
+
+// neighborTableTTL is how long a read of the neighbor table is reused.
+const neighborTableTTL = 2 * time.Second
+
+var defaultNeighborCache = &neighborCache{
+	ttl:  neighborTableTTL,
+	read: readNeighborTable,
+	now:  time.Now,
+}
+
+// ResolveClientMAC looks up the MAC address of ip in the neighbor (ARP)
+// table of the OS, so a query can be matched by the device that sent it
+// even if it does not carry a MAC in EDNS0. The table is cached for a short
+// time. It returns ErrNeighborNotFound if ip is not in the table.
+// Linux and Windows are supported. On Linux the IPv4 table /proc/net/arp
+// is read, on Windows the IPv4 and IPv6 tables of GetIpNetTable2. Other
+// platforms return an error.
+func ResolveClientMAC(ip net.IP) (net.HardwareAddr, error) {
+	return defaultNeighborCache.resolve(ip)
+}
+
+// neighborCache caches the neighbor table for ttl.
+type neighborCache struct {
+	ttl  time.Duration
+	read func() (map[netip.Addr]net.HardwareAddr, error)
+	now  func() time.Time
+
+	mu     sync.Mutex
+	table  map[netip.Addr]net.HardwareAddr
+	readAt time.Time
+}
+
+func (c *neighborCache) resolve(ip net.IP) (net.HardwareAddr, error) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return nil, ErrNeighborNotFound
+	}
+	addr = addr.Unmap()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now := c.now(); c.table == nil || now.Sub(c.readAt) >= c.ttl {
+		t, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		c.table, c.readAt = t, now
+	}
+	mac, ok := c.table[addr]
+	if !ok {
+		return nil, ErrNeighborNotFound
+	}
+	return mac, nil
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package macaddr
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const procNetARP = "/proc/net/arp"
+
+// atfCom is the ATF_COM flag of a completed ARP entry.
+const atfCom = 0x2
+
+func readNeighborTable() (map[netip.Addr]net.HardwareAddr, error) {
+	b, err := os.ReadFile(procNetARP)
+	if err != nil {
+		return nil, err
+	}
+	return parseProcNetARP(bytes.NewReader(b))
+}
+
+// parseProcNetARP parses the content of /proc/net/arp.
+// Incomplete entries and malformed lines are skipped.
+func parseProcNetARP(r io.Reader) (map[netip.Addr]net.HardwareAddr, error) {
+	table := make(map[netip.Addr]net.HardwareAddr)
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// IP address, HW type, Flags, HW address, Mask, Device
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		addr, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[2], 0, 32)
+		if err != nil || flags&atfCom == 0 {
+			continue
+		}
+		mac, err := net.ParseMAC(fields[3])
+		if err != nil {
+			continue
+		}
+		table[addr] = mac
+	}
+	return table, scanner.Err()
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package macaddr
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+const procNetARPFixture = `IP address       HW type     Flags       HW address            Mask     Device
+192.168.1.10     0x1         0x2         aa:bb:cc:dd:ee:ff     *        br-lan
+192.168.1.11     0x1         0x0         00:00:00:00:00:00     *        br-lan
+192.168.1.12     0x1         0x6         00:11:22:33:44:55     *        br-lan
+bad line
+`
+
+func TestParseProcNetARP(t *testing.T) {
+	table, err := parseProcNetARP(strings.NewReader(procNetARPFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table) != 2 {
+		t.Errorf("got %d entries, want 2: %v", len(table), table)
+	}
+	if mac := table[netip.MustParseAddr("192.168.1.10")]; mac.String() != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("192.168.1.10 = %s, want aa:bb:cc:dd:ee:ff", mac)
+	}
+	if _, ok := table[netip.MustParseAddr("192.168.1.11")]; ok {
+		t.Error("incomplete entry should be skipped")
+	}
+}
+
+func TestNeighborCache(t *testing.T) {
+	reads := 0
+	now := time.Unix(0, 0)
+	c := &neighborCache{
+		ttl: time.Second,
+		read: func() (map[netip.Addr]net.HardwareAddr, error) {
+			reads++
+			return parseProcNetARP(strings.NewReader(procNetARPFixture))
+		},
+		now: func() time.Time { return now },
+	}
+
+	mac, err := c.resolve(net.ParseIP("192.168.1.12"))
+	if err != nil || mac.String() != "00:11:22:33:44:55" {
+		t.Errorf("resolve() = %s, %v, want 00:11:22:33:44:55", mac, err)
+	}
+	if _, err := c.resolve(net.ParseIP("192.168.1.99")); !errors.Is(err, ErrNeighborNotFound) {
+		t.Errorf("resolve() error = %v, want ErrNeighborNotFound", err)
+	}
+	if reads != 1 {
+		t.Errorf("table read %d times within ttl, want 1", reads)
+	}
+	now = now.Add(time.Second)
+	if _, err := c.resolve(net.ParseIP("192.168.1.10").To4()); err != nil {
+		t.Error(err)
+	}
+	if reads != 2 {
+		t.Errorf("table should be read again after ttl, got %d reads", reads)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package macaddr
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+)
+
+func readNeighborTable() (map[netip.Addr]net.HardwareAddr, error) {
+	return nil, errors.New("neighbor table lookup is not supported on this platform")
+}
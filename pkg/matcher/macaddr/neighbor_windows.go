@@ -0,0 +1,82 @@
+//go:build windows
+
+package macaddr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/netip"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// GetIpNetTable2 is not wrapped by x/sys/windows.
+var procGetIpNetTable2 = windows.NewLazySystemDLL("iphlpapi.dll").NewProc("GetIpNetTable2")
+
+// nlnsProbe is the first NL_NEIGHBOR_STATE of a resolved entry. Lower
+// states are NlnsUnreachable and NlnsIncomplete.
+const nlnsProbe = 2
+
+// mibIPNetRow2 is MIB_IPNET_ROW2 of netioapi.h.
+type mibIPNetRow2 struct {
+	address               [28]byte // SOCKADDR_INET
+	interfaceIndex        uint32
+	interfaceLuid         uint64
+	physicalAddress       [32]byte
+	physicalAddressLength uint32
+	state                 int32
+	flags                 uint8
+	_                     [3]byte
+	reachabilityTime      uint32
+}
+
+// mibIPNetTable2 is the header of MIB_IPNET_TABLE2, which is followed by
+// numEntries rows, aligned like the uint64 of a row.
+type mibIPNetTable2 struct {
+	numEntries uint32
+	_          uint32
+}
+
+// readNeighborTable reads the IPv4 and IPv6 neighbor tables with
+// GetIpNetTable2.
+func readNeighborTable() (map[netip.Addr]net.HardwareAddr, error) {
+	var table *mibIPNetTable2
+	r0, _, _ := procGetIpNetTable2.Call(uintptr(windows.AF_UNSPEC), uintptr(unsafe.Pointer(&table)))
+	if r0 != 0 {
+		return nil, fmt.Errorf("GetIpNetTable2, %w", windows.Errno(r0))
+	}
+	defer windows.FreeMibTable(unsafe.Pointer(table))
+
+	rows := unsafe.Slice(
+		(*mibIPNetRow2)(unsafe.Add(unsafe.Pointer(table), unsafe.Sizeof(*table))),
+		table.numEntries,
+	)
+	return parseIPNetRows(rows), nil
+}
+
+// parseIPNetRows converts neighbor rows to a table. Unresolved entries and
+// entries without a hardware address are skipped.
+func parseIPNetRows(rows []mibIPNetRow2) map[netip.Addr]net.HardwareAddr {
+	table := make(map[netip.Addr]net.HardwareAddr, len(rows))
+	for i := range rows {
+		r := &rows[i]
+		n := r.physicalAddressLength
+		if r.state < nlnsProbe || n == 0 || n > uint32(len(r.physicalAddress)) {
+			continue
+		}
+		var addr netip.Addr
+		switch binary.LittleEndian.Uint16(r.address[:2]) {
+		case windows.AF_INET: // sockaddr_in, family, port, addr
+			addr = netip.AddrFrom4([4]byte(r.address[4:8]))
+		case windows.AF_INET6: // sockaddr_in6, family, port, flowinfo, addr
+			addr = netip.AddrFrom16([16]byte(r.address[8:24]))
+		default:
+			continue
+		}
+		table[addr] = net.HardwareAddr(bytes.Clone(r.physicalAddress[:n]))
+	}
+	return table
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+package macaddr
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+func testIPNetRow(addr netip.Addr, mac string, state int32) mibIPNetRow2 {
+	var r mibIPNetRow2
+	if addr.Is4() {
+		binary.LittleEndian.PutUint16(r.address[:2], windows.AF_INET)
+		a := addr.As4()
+		copy(r.address[4:8], a[:])
+	} else {
+		binary.LittleEndian.PutUint16(r.address[:2], windows.AF_INET6)
+		a := addr.As16()
+		copy(r.address[8:24], a[:])
+	}
+	r.physicalAddressLength = uint32(copy(r.physicalAddress[:], mustParseMAC(mac)))
+	r.state = state
+	return r
+}
+
+func TestParseIPNetRows(t *testing.T) {
+	if size := unsafe.Sizeof(mibIPNetRow2{}); size != 88 {
+		t.Fatalf("sizeof(MIB_IPNET_ROW2) = %d, want 88", size)
+	}
+	const reachable = 5
+	rows := []mibIPNetRow2{
+		testIPNetRow(netip.MustParseAddr("192.168.1.10"), "aa:bb:cc:dd:ee:ff", reachable),
+		testIPNetRow(netip.MustParseAddr("fe80::1"), "00:11:22:33:44:55", reachable),
+		testIPNetRow(netip.MustParseAddr("192.168.1.11"), "00:00:00:00:00:00", 1), // incomplete
+	}
+	table := parseIPNetRows(rows)
+	if len(table) != 2 {
+		t.Errorf("got %d entries, want 2: %v", len(table), table)
+	}
+	if mac := table[netip.MustParseAddr("192.168.1.10")]; mac.String() != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("192.168.1.10 = %s, want aa:bb:cc:dd:ee:ff", mac)
+	}
+	if mac := table[netip.MustParseAddr("fe80::1")]; mac.String() != "00:11:22:33:44:55" {
+		t.Errorf("fe80::1 = %s, want 00:11:22:33:44:55", mac)
+	}
+}
+
+func TestReadNeighborTable(t *testing.T) {
+	if _, err := readNeighborTable(); err != nil {
+		t.Fatal(err)
+	}
+}
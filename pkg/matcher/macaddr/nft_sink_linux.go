@@ -0,0 +1,116 @@
+//go:build linux
+
+package macaddr
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/nftables"
+)
+
+// NftSetSinkOpts are the options of NewNftSetSink.
+type NftSetSinkOpts struct {
+	Conn        *nftables.Conn // Required.
+	TableFamily nftables.TableFamily
+	TableName   string // Required.
+	SetName     string // Required. Its type must be ether_addr.
+}
+
+// NftSetSink is a MatchSink that keeps the elements of an nftables set
+// equal to the entries of the matcher, so rules can act on the devices in
+// kernel, e.g. "ether saddr @macs meta mark set 1".
+// Each Sync only adds and deletes the changed elements, in one batch.
+type NftSetSink struct {
+	opts  NftSetSinkOpts
+	table *nftables.Table
+
+	m      sync.Mutex
+	synced bool // cur has been loaded from the set
+	cur    map[[6]byte]struct{}
+}
+
+// NewNftSetSink inits NftSetSink. The set is not accessed until the first
+// Sync, which also deletes the elements that are not entries of the matcher.
+func NewNftSetSink(opts NftSetSinkOpts) *NftSetSink {
+	return &NftSetSink{
+		opts: opts,
+		table: &nftables.Table{
+			Name:   opts.TableName,
+			Family: opts.TableFamily,
+		},
+	}
+}
+
+// Sync implements MatchSink.
+func (s *NftSetSink) Sync(macs [][6]byte) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	set, err := s.opts.Conn.GetSetByName(s.table, s.opts.SetName)
+	if err != nil {
+		return fmt.Errorf("failed to get set, %w", err)
+	}
+	if !s.synced {
+		cur, err := s.loadElems(set)
+		if err != nil {
+			return err
+		}
+		s.cur = cur
+		s.synced = true
+	}
+
+	added, removed := sinkDiff(s.cur, macs)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	if len(removed) > 0 {
+		if err := s.opts.Conn.SetDeleteElements(set, setElems(removed)); err != nil {
+			return err
+		}
+	}
+	if len(added) > 0 {
+		if err := s.opts.Conn.SetAddElements(set, setElems(added)); err != nil {
+			return err
+		}
+	}
+	if err := s.opts.Conn.Flush(); err != nil {
+		// The batch may or may not have been applied, reload the set on
+		// the next Sync.
+		s.synced = false
+		return err
+	}
+
+	next := make(map[[6]byte]struct{}, len(macs))
+	for _, k := range macs {
+		next[k] = struct{}{}
+	}
+	s.cur = next
+	return nil
+}
+
+// loadElems returns the current elements of set.
+func (s *NftSetSink) loadElems(set *nftables.Set) (map[[6]byte]struct{}, error) {
+	elems, err := s.opts.Conn.GetSetElements(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get set elements, %w", err)
+	}
+	cur := make(map[[6]byte]struct{}, len(elems))
+	for _, e := range elems {
+		if len(e.Key) < 6 {
+			continue
+		}
+		cur[[6]byte(e.Key)] = struct{}{}
+	}
+	return cur, nil
+}
+
+func setElems(macs [][6]byte) []nftables.SetElement {
+	elems := make([]nftables.SetElement, 0, len(macs))
+	for _, k := range macs {
+		elems = append(elems, nftables.SetElement{Key: k[:]})
+	}
+	return elems
+}
+
+var _ MatchSink = (*NftSetSink)(nil)
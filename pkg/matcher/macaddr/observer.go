@@ -0,0 +1,32 @@
+package macaddr
+
+// MatchObserver is notified of the result of every match, e.g. to export
+// hit and miss counters as metrics. It must be safe for concurrent use if
+// the matcher is used concurrently.
+type MatchObserver interface {
+	ObserveMatch(hit bool)
+}
+
+// SetObserver sets the observer of Match. A nil o removes it.
+// It must not be called concurrently with Match.
+func (m *Matcher) SetObserver(o MatchObserver) {
+	m.observer = o
+}
+
+// SetObserver sets the observer of Match and MatchOutcome. A grace hit
+// is observed as a hit. A nil o removes it.
+func (d *DynamicMatcher) SetObserver(o MatchObserver) {
+	d.l.Lock()
+	defer d.l.Unlock()
+	d.observer = o
+}
+
+// PerMatcherLen returns the Len of each matcher in the group, in the order
+// they were appended. The exclude set is not included.
+func (mg *LocalMatcherGroup) PerMatcherLen() []int {
+	res := make([]int, 0, len(mg.g))
+	for _, m := range mg.g {
+		res = append(res, m.Len())
+	}
+	return res
+}
@@ -0,0 +1,89 @@
+package macaddr
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+type countingObserver struct {
+	hit, miss atomic.Int64
+}
+
+func (o *countingObserver) ObserveMatch(hit bool) {
+	if hit {
+		o.hit.Add(1)
+	} else {
+		o.miss.Add(1)
+	}
+}
+
+func TestMatchObserver(t *testing.T) {
+	m := newTestMatcher(t, "00:11:22:33:44:55")
+	mo := new(countingObserver)
+	m.SetObserver(mo)
+
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	if err := d.Update([]byte("00:11:22:33:44:55\n")); err != nil {
+		t.Fatal(err)
+	}
+	do := new(countingObserver)
+	d.SetObserver(do)
+
+	for _, s := range []string{"00:11:22:33:44:55", "00:11:22:33:44:66", "00:11:22:33:44:55", "aa:bb:cc:dd:ee:ff"} {
+		m.Match(mustParseMAC(s))
+		d.Match(mustParseMAC(s))
+	}
+	d.MatchOutcome(mustParseMAC("00:11:22:33:44:55"))
+
+	if hit, miss := mo.hit.Load(), mo.miss.Load(); hit != 2 || miss != 2 {
+		t.Errorf("Matcher observed %d hits, %d misses, want 2, 2", hit, miss)
+	}
+	if hit, miss := do.hit.Load(), do.miss.Load(); hit != 3 || miss != 2 {
+		t.Errorf("DynamicMatcher observed %d hits, %d misses, want 3, 2", hit, miss)
+	}
+
+	m.SetObserver(nil)
+	m.Match(mustParseMAC("00:11:22:33:44:55"))
+	if mo.hit.Load() != 2 {
+		t.Error("removed observer should not be called")
+	}
+}
+
+func TestMatchObserver_WithTag(t *testing.T) {
+	m := NewMatcher()
+	if err := m.AddWithTag("00:11:22:33:44:55", "tv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("aa:bb:cc", struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+	o := new(countingObserver)
+	m.SetObserver(o)
+
+	for _, s := range []string{"00:11:22:33:44:55", "aa:bb:cc:dd:ee:ff", "00:11:22:33:44:66"} {
+		m.MatchWithTag(mustParseMAC(s))
+		m.MatchWithMeta(mustParseMAC(s))
+	}
+	if hit, miss := o.hit.Load(), o.miss.Load(); hit != 4 || miss != 2 {
+		t.Errorf("observed %d hits, %d misses, want 4, 2", hit, miss)
+	}
+	if tag, ok := m.MatchWithTag(mustParseMAC("00:11:22:33:44:55")); !ok || tag != "tv" {
+		t.Errorf("MatchWithTag() = %q, %v, want \"tv\", true", tag, ok)
+	}
+}
+
+func TestLocalMatcherGroup_PerMatcherLen(t *testing.T) {
+	mg := &LocalMatcherGroup{}
+	mg.Append(newTestMatcher(t, "00:11:22:33:44:55", "00:11:22:33:44:66"))
+	mg.Append(newTestMatcher(t, "aa:bb:cc"))
+	mg.AppendExclude(newTestMatcher(t, "00:11:22:33:44:55"))
+	if got, want := mg.PerMatcherLen(), []int{2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PerMatcherLen() = %v, want %v", got, want)
+	}
+	if mg.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", mg.Len())
+	}
+}
@@ -0,0 +1,50 @@
+package macaddr
+
+// Overlap returns the number of exact EUI-48 entries present in both a
+// and b. Other entries, e.g. prefixes, are not compared. It iterates the
+// smaller set of entries, so the cost is O(min) of their counts.
+func Overlap(a, b *Matcher) int {
+	return overlap(a, b, 0)
+}
+
+// OverlapMatrix returns the pairwise overlap counts of ms, where res[i][j]
+// is the overlap of ms[i] and ms[j], see Overlap, and res[i][i] is the
+// number of exact EUI-48 entries of ms[i].
+// This is meant for offline analysis. If maxSample > 0, at most maxSample
+// entries of the smaller matcher of each pair are examined and the count
+// is extrapolated from that sample, which bounds the cost for huge lists.
+func OverlapMatrix(ms []*Matcher, maxSample int) [][]int {
+	res := make([][]int, len(ms))
+	for i := range res {
+		res[i] = make([]int, len(ms))
+	}
+	for i := range ms {
+		res[i][i] = len(ms[i].macs)
+		for j := i + 1; j < len(ms); j++ {
+			n := overlap(ms[i], ms[j], maxSample)
+			res[i][j] = n
+			res[j][i] = n
+		}
+	}
+	return res
+}
+
+func overlap(a, b *Matcher, maxSample int) int {
+	if len(a.macs) > len(b.macs) {
+		a, b = b, a
+	}
+	n, examined := 0, 0
+	for k := range a.macs {
+		if maxSample > 0 && examined >= maxSample {
+			break
+		}
+		examined++
+		if _, ok := b.macs[k]; ok {
+			n++
+		}
+	}
+	if examined > 0 && examined < len(a.macs) {
+		return n * len(a.macs) / examined
+	}
+	return n
+}
@@ -0,0 +1,53 @@
+package macaddr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverlapMatrix(t *testing.T) {
+	a := newTestMatcher(t, "00:00:00:00:00:01", "00:00:00:00:00:02", "00:00:00:00:00:03")
+	b := newTestMatcher(t, "00:00:00:00:00:02", "00:00:00:00:00:03", "00:00:00:00:00:04")
+	c := newTestMatcher(t, "00:00:00:00:00:03", "00:00:00:00:00:05")
+	d := newTestMatcher(t, "ff:00:00:00:00:01")
+
+	want := [][]int{
+		{3, 2, 1, 0},
+		{2, 3, 1, 0},
+		{1, 1, 2, 0},
+		{0, 0, 0, 1},
+	}
+	if got := OverlapMatrix([]*Matcher{a, b, c, d}, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("OverlapMatrix() = %v, want %v", got, want)
+	}
+	if got := Overlap(a, b); got != 2 {
+		t.Errorf("Overlap() = %d, want 2", got)
+	}
+
+	// Identical sets extrapolate to the full size regardless of the sample.
+	if got := OverlapMatrix([]*Matcher{a, a}, 1)[0][1]; got != 3 {
+		t.Errorf("sampled overlap = %d, want 3", got)
+	}
+}
+
+func TestOverlapMatrix_MixedKinds(t *testing.T) {
+	a := newTestMatcher(t, "00:00:00:00:00:01",
+		"10:00:01", "10:00:02", "10:00:03", "10:00:04", "10:00:05",
+		"10:00:06", "10:00:07", "10:00:08", "10:00:09",
+		"20:00:00:00:00:00-20:00:00:00:00:ff",
+		"30:00:*:*:*:*",
+		"00:00:00:00:00:01:02:03",
+	)
+	b := newTestMatcher(t, "00:00:00:00:00:01", "00:00:00:00:00:02")
+
+	want := [][]int{
+		{1, 1},
+		{1, 2},
+	}
+	if got := OverlapMatrix([]*Matcher{a, b}, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("OverlapMatrix() = %v, want %v", got, want)
+	}
+	if got := Overlap(a, b); got != 1 {
+		t.Errorf("Overlap() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,93 @@
+package macaddr
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MacParserFunc parses the data of a provider into a matcher.
+type MacParserFunc func(b []byte) (LocalMatcher, error)
+
+// macParserFunc is a MacParserFunc that also filters by site, for the
+// built-in formats that have a site column.
+type macParserFunc func(b []byte, sf siteFilter) (LocalMatcher, error)
+
+var macParserRegister struct {
+	sync.RWMutex
+	m map[string]macParserFunc
+}
+
+func init() {
+	registerMacParser("text", func(b []byte, sf siteFilter) (LocalMatcher, error) {
+		return parseTextMacFile(b, sf, 0, nil)
+	})
+	registerMacParser("binary", func(b []byte, _ siteFilter) (LocalMatcher, error) {
+		return ParseBinaryMacFile(b)
+	})
+	registerMacParser("json", func(b []byte, _ siteFilter) (LocalMatcher, error) {
+		return ParseJSONMacFile(b)
+	})
+	registerMacParser("dhcp-lease", func(b []byte, _ siteFilter) (LocalMatcher, error) {
+		return ParseDHCPLeaseFile(b)
+	})
+	registerMacParser("ieee-oui", func(b []byte, _ siteFilter) (LocalMatcher, error) {
+		return ParseIEEEOUICSV(b)
+	})
+}
+
+// RegisterMacParser registers the format name, which provider and file
+// entries may select with a "format" query, e.g.
+// "provider:mylist?format=name". The built-in formats are "text",
+// "binary", "json", "dhcp-lease" and "ieee-oui", see ParseIEEEOUICSV.
+// Without a format, the data is parsed as text, binary or json, which is
+// detected automatically.
+// If the format has been registered, RegisterMacParser will panic.
+func RegisterMacParser(name string, fn MacParserFunc) {
+	registerMacParser(name, func(b []byte, _ siteFilter) (LocalMatcher, error) {
+		return fn(b)
+	})
+}
+
+func registerMacParser(name string, fn macParserFunc) {
+	macParserRegister.Lock()
+	defer macParserRegister.Unlock()
+	if _, ok := macParserRegister.m[name]; ok {
+		panic(fmt.Sprintf("duplicate mac parser format [%s]", name))
+	}
+	if macParserRegister.m == nil {
+		macParserRegister.m = make(map[string]macParserFunc)
+	}
+	macParserRegister.m[name] = fn
+}
+
+// getMacParser returns the parser of the format name.
+func getMacParser(name string) (macParserFunc, error) {
+	macParserRegister.RLock()
+	defer macParserRegister.RUnlock()
+	if fn, ok := macParserRegister.m[name]; ok {
+		return fn, nil
+	}
+	names := make([]string, 0, len(macParserRegister.m))
+	for n := range macParserRegister.m {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return nil, fmt.Errorf("unknown format %s, registered formats: %v", name, names)
+}
+
+// cutFormat splits the reference of an entry, e.g. "mylist?format=json",
+// into the reference and the value of its format query.
+func cutFormat(ref string) (string, string, error) {
+	ref, rawQuery, ok := strings.Cut(ref, "?")
+	if !ok {
+		return ref, "", nil
+	}
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid query %s, %w", rawQuery, err)
+	}
+	return ref, q.Get("format"), nil
+}
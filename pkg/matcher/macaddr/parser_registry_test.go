@@ -0,0 +1,62 @@
+package macaddr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/pmkol/mosdns-x/pkg/data_provider"
+)
+
+func init() {
+	// "csv" reads the first column of comma separated lines.
+	RegisterMacParser("csv", func(b []byte) (LocalMatcher, error) {
+		m := NewMatcher()
+		for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			mac, _, _ := strings.Cut(line, ",")
+			if err := m.Add(mac, struct{}{}); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	})
+}
+
+func TestRegisterMacParser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macs.csv")
+	if err := os.WriteFile(path, []byte("00:11:22:33:44:55,tv\naa:bb:cc:dd:ee:ff,phone\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dp, err := data_provider.NewDataProvider(zap.NewNop(), data_provider.DataProviderConfig{File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+	dm := data_provider.NewDataManager()
+	dm.AddDataProvider("devices", dp)
+
+	mg, err := BatchLoadMacProvider([]string{"provider:devices?format=csv"}, dm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mg.Close()
+	if !mg.Match(mustParseMAC("aa:bb:cc:dd:ee:ff")) || mg.Len() != 2 {
+		t.Errorf("Match() = false or Len() = %d, want a match and 2", mg.Len())
+	}
+
+	// Without a format, the data is detected as text, which rejects it.
+	if _, err := BatchLoadMacProvider([]string{"provider:devices"}, dm); err == nil {
+		t.Error("expected error for csv data parsed as text")
+	}
+
+	_, err = BatchLoadMacProvider([]string{"provider:devices?format=xml"}, dm)
+	if err == nil || !strings.Contains(err.Error(), "[binary csv dhcp-lease ieee-oui json text]") {
+		t.Errorf("error = %v, want one listing the registered formats", err)
+	}
+	if errs := ValidatePatterns([]string{"file:/macs.xml?format=xml", "provider:devices?format=csv"}); len(errs) != 1 {
+		t.Errorf("ValidatePatterns() = %v, want 1 error", errs)
+	}
+}
@@ -0,0 +1,143 @@
+package macaddr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Kind is the kind of a pattern accepted by Matcher.Add.
+type Kind int
+
+const (
+	// KindExact is a single EUI-48 or EUI-64 address.
+	KindExact Kind = iota + 1
+	// KindPrefix is a 3-octet OUI prefix, e.g. "aa:bb:cc".
+	KindPrefix
+	// KindRange is an inclusive EUI-48 range, e.g. "00:11:22:33:44:00-00:11:22:33:44:ff".
+	KindRange
+	// KindWildcard is an EUI-48 pattern with "*" octets, e.g. "aa:bb:*:dd:*:*".
+	KindWildcard
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindExact:
+		return "exact"
+	case KindPrefix:
+		return "prefix"
+	case KindRange:
+		return "range"
+	case KindWildcard:
+		return "wildcard"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// ClassifyPattern parses pattern with the rules of Matcher.Add, without a
+// matcher, and returns its kind. A range whose ends are equal is exact, as
+// Add stores it as a single address. Addresses must be 6 or 8 bytes, like
+// for a matcher created by NewMatcher.
+func ClassifyPattern(pattern string) (Kind, error) {
+	p, err := parsePattern(pattern)
+	if err != nil {
+		return 0, err
+	}
+	var m Matcher
+	if p.kind == KindExact && !m.validLen(len(p.mac)) {
+		return 0, m.lenError(len(p.mac))
+	}
+	return p.kind, nil
+}
+
+// parsedPattern is a parsed pattern of Matcher.Add.
+// Only the field of its kind is set.
+type parsedPattern struct {
+	kind   Kind
+	mac    net.HardwareAddr // KindExact
+	oui    [3]byte          // KindPrefix
+	r      macRange         // KindRange
+	masked maskedMAC        // KindWildcard
+}
+
+// classify detects the kind of pattern by its syntax alone, which is the
+// only place that does so. It is used by Matcher.Add and so by Load and
+// the text loaders.
+// A pattern that does not look like another kind is exact, so an invalid
+// pattern is reported by the parser of the kind the user likely meant.
+func classify(pattern string) Kind {
+	if _, _, ok := cutRange(pattern); ok {
+		return KindRange
+	}
+	if isWildcardPattern(pattern) {
+		return KindWildcard
+	}
+	if isPrefixPattern(pattern) {
+		return KindPrefix
+	}
+	return KindExact
+}
+
+// isPrefixPattern reports whether s uses the OUI prefix syntax, three
+// octets with colon or dash separators.
+func isPrefixPattern(s string) bool {
+	return strings.Count(s, ":")+strings.Count(s, "-") == 2
+}
+
+// parsePattern classifies pattern and parses it with the parser of its
+// kind, see classify.
+// The address length of an exact pattern is not checked, since it depends
+// on the matcher, see Matcher.validLen.
+func parsePattern(pattern string) (parsedPattern, error) {
+	switch classify(pattern) {
+	case KindRange:
+		lo, hi, _ := cutRange(pattern)
+		r, err := parseRange(lo, hi)
+		if err != nil {
+			return parsedPattern{}, err
+		}
+		if r.lo == r.hi {
+			key := uint64ToMAC48(r.lo)
+			return parsedPattern{kind: KindExact, mac: key[:]}, nil
+		}
+		return parsedPattern{kind: KindRange, r: r}, nil
+	case KindWildcard:
+		p, err := parseWildcardMAC(pattern)
+		if err != nil {
+			return parsedPattern{}, err
+		}
+		return parsedPattern{kind: KindWildcard, masked: p}, nil
+	case KindPrefix:
+		p, err := parsePartialMAC(pattern)
+		if err != nil {
+			return parsedPattern{}, err
+		}
+		return parsedPattern{kind: KindPrefix, oui: [3]byte(p)}, nil
+	default:
+		hwAddr, err := parseMAC(pattern)
+		if err != nil {
+			return parsedPattern{}, fmt.Errorf("invalid MAC address %s: %w", pattern, err)
+		}
+		return parsedPattern{kind: KindExact, mac: hwAddr}, nil
+	}
+}
+
+// parseMAC is like net.ParseMAC, but also accepts an EUI-48 address as 12
+// hex digits without separators, e.g. "001122334455", as some switches
+// export it.
+func parseMAC(s string) (net.HardwareAddr, error) {
+	if len(s) != 12 {
+		return net.ParseMAC(s)
+	}
+	mac := make(net.HardwareAddr, 6)
+	for i := range mac {
+		b, ok := hexByte(s[2*i], s[2*i+1])
+		if !ok {
+			return nil, errors.New("12-character address must be hex digits only")
+		}
+		mac[i] = b
+	}
+	return mac, nil
+}
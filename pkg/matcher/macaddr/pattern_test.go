@@ -0,0 +1,127 @@
+package macaddr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    Kind
+		wantErr bool
+	}{
+		{"aa:bb:cc:dd:ee:ff", KindExact, false},
+		{"aabb.ccdd.eeff", KindExact, false},
+		{"aa:bb:cc:dd:ee:ff:00:11", KindExact, false},
+		{"aa:bb:cc", KindPrefix, false},
+		{"AA-BB-CC", KindPrefix, false},
+		{"00:11:22:33:44:00-00:11:22:33:44:ff", KindRange, false},
+		{"00:11:22:33:44:00-00:11:22:33:44:00", KindExact, false},
+		{"aa:bb:*:dd:*:*", KindWildcard, false},
+		{"aa:bb:cc:dd:ee:ff:", 0, true},
+		{"00:11:22:33:44:ff-00:11:22:33:44:00", 0, true},
+		{"aa:*:cc", 0, true},
+		{"00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ClassifyPattern(tt.pattern)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ClassifyPattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ClassifyPattern(%q) = %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    Kind
+	}{
+		{"aa:bb:cc:dd:ee:ff", KindExact},
+		{"aa-bb-cc-dd-ee-ff", KindExact},
+		{"aabb.ccdd.eeff", KindExact},
+		{"aabbccddeeff", KindExact},
+		{"aa:bb:cc:dd:ee:ff:00:11", KindExact},
+		{"aa:bb:cc", KindPrefix},
+		{"aa-bb-cc", KindPrefix},
+		{"aa:bb:zz", KindPrefix},
+		{"00:11:22:33:44:00-00:11:22:33:44:ff", KindRange},
+		{"00-11-22-33-44-00-00-11-22-33-44-ff", KindRange},
+		{"0011.2233.4400-0011.2233.44ff", KindRange},
+		{"aa:bb:*:dd:*:*", KindWildcard},
+		{"aa:*:cc", KindWildcard},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.pattern); got != tt.want {
+			t.Errorf("classify(%q) = %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestMatcher_AddErrorByKind(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string // prefix of the error
+	}{
+		{"aa:bb:cc:dd:ee:zz", "invalid MAC address aa:bb:cc:dd:ee:zz"},
+		{"aa:bb:zz", "invalid MAC prefix aa:bb:zz"},
+		{"00:11:22:33:44:zz-00:11:22:33:44:ff", "invalid range start 00:11:22:33:44:zz"},
+		{"aa:bb:*:dd:*:zz", "invalid wildcard MAC aa:bb:*:dd:*:zz"},
+	}
+	for _, tt := range tests {
+		err := NewMatcher().Add(tt.pattern, struct{}{})
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("Add(%q) error = %v, want prefix %q", tt.pattern, err, tt.want)
+		}
+	}
+}
+
+func TestLoadFromTextReader_AllKinds(t *testing.T) {
+	const data = `
+00:11:22:33:44:55
+aabbccddeeff
+de:ad:be
+10:00:00:00:00:00-10:00:00:00:00:ff
+20:00:*:*:00:01
+00:11:22:33:44:55:66:77
+`
+	m := NewMatcher()
+	if err := LoadFromTextReader(m, strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		mac  string
+		want bool
+	}{
+		{"00:11:22:33:44:55", true},       // exact
+		{"aa:bb:cc:dd:ee:ff", true},       // bare exact
+		{"de:ad:be:01:02:03", true},       // prefix
+		{"10:00:00:00:00:80", true},       // range
+		{"20:00:12:34:00:01", true},       // wildcard
+		{"00:11:22:33:44:55:66:77", true}, // EUI-64
+		{"00:11:22:33:44:56", false},
+		{"de:ad:bf:01:02:03", false},
+		{"10:00:00:00:01:00", false},
+		{"20:00:12:34:00:02", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(mustParseMAC(tt.mac)); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.mac, got, tt.want)
+		}
+	}
+	if m.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", m.Len())
+	}
+
+	mm := NewMatcher()
+	if err := BatchLoad(mm, strings.Fields(data)); err != nil {
+		t.Fatal(err)
+	}
+	if mm.Len() != m.Len() {
+		t.Errorf("BatchLoad() Len() = %d, want %d", mm.Len(), m.Len())
+	}
+}
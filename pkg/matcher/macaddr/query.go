@@ -0,0 +1,114 @@
+package macaddr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+)
+
+// QueryPrefix returns all exact entries in the matcher whose leading octets
+// equal prefix, sorted in ascending order. prefix is a partial MAC address
+// of 1 to 5 octets separated by ':' or '-', e.g. "aa:bb:cc".
+// This is a lookup over stored entries, intended for admin tools, and it
+// scans the whole matcher.
+func (m *Matcher) QueryPrefix(prefix string) ([]net.HardwareAddr, error) {
+	p, err := parsePartialMAC(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys [][6]byte
+	for k := range m.macs {
+		if bytes.HasPrefix(k[:], p) {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b [6]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	res := make([]net.HardwareAddr, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, net.HardwareAddr(k[:]))
+	}
+	return res, nil
+}
+
+// OUICount returns the number of distinct OUI (vendor) prefixes among the
+// exact EUI-48 entries of the matcher. It is computed on demand by scanning
+// the matcher.
+func (m *Matcher) OUICount() int {
+	ouis := make(map[[3]byte]struct{})
+	for k := range m.macs {
+		ouis[[3]byte(k[:3])] = struct{}{}
+	}
+	return len(ouis)
+}
+
+// ForEach calls fn for each exact EUI-48 entry of the matcher, until fn
+// returns false. The iteration order is unspecified. Like Entries, other
+// entries, e.g. EUI-64 addresses or patterns, are not visited. fn may keep
+// mac, but it must not modify the matcher.
+func (m *Matcher) ForEach(fn func(mac net.HardwareAddr) bool) {
+	for k := range m.macs {
+		if !fn(net.HardwareAddr(k[:])) {
+			return
+		}
+	}
+}
+
+// MACsForTag returns the EUI-48 entries tagged with tag, sorted in
+// ascending order. It is the inverse of MatchWithTag, e.g. to list the
+// devices of a group. The lookup uses an index that is only built once
+// the matcher has tags.
+func (m *Matcher) MACsForTag(tag string) []net.HardwareAddr {
+	keys := slices.Clone(m.tagIndex[tag])
+	slices.SortFunc(keys, func(a, b [6]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	res := make([]net.HardwareAddr, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, net.HardwareAddr(k[:]))
+	}
+	return res
+}
+
+// unindexTag removes key from the index of tag.
+func (m *Matcher) unindexTag(tag string, key [6]byte) {
+	keys := m.tagIndex[tag]
+	if i := slices.Index(keys, key); i >= 0 {
+		keys = slices.Delete(keys, i, i+1)
+	}
+	if len(keys) == 0 {
+		delete(m.tagIndex, tag)
+		return
+	}
+	m.tagIndex[tag] = keys
+}
+
+// parsePartialMAC parses 1 to 5 leading octets of a MAC address.
+func parsePartialMAC(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	octets := strings.FieldsFunc(s, func(r rune) bool { return r == ':' || r == '-' })
+	if len(octets) < 1 || len(octets) > 5 {
+		return nil, fmt.Errorf("invalid MAC prefix %s: expect 1-5 octets, got %d", s, len(octets))
+	}
+	if strings.Count(s, ":")+strings.Count(s, "-") != len(octets)-1 {
+		return nil, fmt.Errorf("invalid MAC prefix %s: malformed separator", s)
+	}
+	p := make([]byte, 0, len(octets))
+	for _, o := range octets {
+		if len(o) != 2 {
+			return nil, fmt.Errorf("invalid MAC prefix %s: octet %q must be 2 hex digits", s, o)
+		}
+		b, err := hex.DecodeString(o)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAC prefix %s: %w", s, err)
+		}
+		p = append(p, b[0])
+	}
+	return p, nil
+}
@@ -0,0 +1,137 @@
+package macaddr
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestMatcher_QueryPrefix(t *testing.T) {
+	m := newTestMatcher(t,
+		"aa:bb:cc:00:00:02",
+		"aa:bb:cc:00:00:01",
+		"aa:bb:cd:00:00:01",
+		"aa:bc:00:00:00:01",
+		"11:22:33:44:55:66",
+	)
+
+	tests := []struct {
+		prefix  string
+		want    []string
+		wantErr bool
+	}{
+		{prefix: "aa", want: []string{"aa:bb:cc:00:00:01", "aa:bb:cc:00:00:02", "aa:bb:cd:00:00:01", "aa:bc:00:00:00:01"}},
+		{prefix: "aa:bb", want: []string{"aa:bb:cc:00:00:01", "aa:bb:cc:00:00:02", "aa:bb:cd:00:00:01"}},
+		{prefix: "AA-BB-CC", want: []string{"aa:bb:cc:00:00:01", "aa:bb:cc:00:00:02"}},
+		{prefix: "aa:bb:cc:00", want: []string{"aa:bb:cc:00:00:01", "aa:bb:cc:00:00:02"}},
+		{prefix: "aa:bb:cc:00:00", want: []string{"aa:bb:cc:00:00:01", "aa:bb:cc:00:00:02"}},
+		{prefix: "ff:ff", want: []string{}},
+		{prefix: "", wantErr: true},
+		{prefix: "aa:bb:cc:dd:ee:ff", wantErr: true},
+		{prefix: "aa::bb", wantErr: true},
+		{prefix: "a:bb", wantErr: true},
+		{prefix: "zz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, err := m.QueryPrefix(tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryPrefix() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want := make([]net.HardwareAddr, 0, len(tt.want))
+			for _, s := range tt.want {
+				want = append(want, mustParseMAC(s))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("QueryPrefix() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMatcher_OUICount(t *testing.T) {
+	m := newTestMatcher(t,
+		"ac:de:48:00:00:01",
+		"ac:de:48:00:00:02",
+		"00:1a:2b:00:00:01",
+	)
+	if got := m.OUICount(); got != 2 {
+		t.Errorf("OUICount() = %d, want 2", got)
+	}
+	if got := NewMatcher().OUICount(); got != 0 {
+		t.Errorf("OUICount() of empty matcher = %d, want 0", got)
+	}
+}
+
+func TestMatcher_ForEach(t *testing.T) {
+	m := newTestMatcher(t,
+		"ac:de:48:00:00:01",
+		"ac:de:48:00:00:02",
+		"00:1a:2b:00:00:01",
+	)
+	seen := make(map[string]bool)
+	m.ForEach(func(mac net.HardwareAddr) bool {
+		seen[mac.String()] = true
+		return true
+	})
+	if len(seen) != m.Len() {
+		t.Errorf("ForEach visited %d entries, want %d", len(seen), m.Len())
+	}
+	if !seen["00:1a:2b:00:00:01"] {
+		t.Errorf("ForEach did not visit 00:1a:2b:00:00:01, visited %v", seen)
+	}
+
+	n := 0
+	m.ForEach(func(mac net.HardwareAddr) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("ForEach called fn %d times after it returned false, want 1", n)
+	}
+}
+
+func TestMatcher_MACsForTag(t *testing.T) {
+	m := NewMatcher()
+	for _, e := range [][2]string{
+		{"00:11:22:33:44:02", "living-room"},
+		{"00:11:22:33:44:01", "living-room"},
+		{"00:11:22:33:44:03", "bedroom"},
+	} {
+		if err := m.AddWithTag(e[0], e[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(tag string, want ...string) {
+		t.Helper()
+		var got []string
+		for _, mac := range m.MACsForTag(tag) {
+			got = append(got, mac.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("MACsForTag(%q) = %v, want %v", tag, got, want)
+		}
+	}
+	check("living-room", "00:11:22:33:44:01", "00:11:22:33:44:02")
+	check("bedroom", "00:11:22:33:44:03")
+	check("kitchen")
+
+	// Retagging and removing update the index.
+	if err := m.AddWithTag("00:11:22:33:44:02", "bedroom"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove("00:11:22:33:44:03"); err != nil {
+		t.Fatal(err)
+	}
+	check("living-room", "00:11:22:33:44:01")
+	check("bedroom", "00:11:22:33:44:02")
+
+	if NewMatcher().tagIndex != nil {
+		t.Error("untagged matcher should not allocate the tag index")
+	}
+}
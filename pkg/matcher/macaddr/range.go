@@ -0,0 +1,106 @@
+package macaddr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// macRange is an inclusive EUI-48 range.
+type macRange struct {
+	lo, hi uint64
+}
+
+// String returns r as a range pattern, e.g.
+// "00:11:22:33:44:00-00:11:22:33:44:ff".
+func (r macRange) String() string {
+	lo, hi := uint64ToMAC48(r.lo), uint64ToMAC48(r.hi)
+	return FormatMAC(lo) + "-" + FormatMAC(hi)
+}
+
+// rangeList is a list of possibly overlapping ranges sorted by lo.
+// maxHi[i] is the max hi of ranges[:i+1], so a lookup only needs one
+// binary search even if ranges overlap.
+type rangeList struct {
+	ranges []macRange
+	maxHi  []uint64
+}
+
+func (l *rangeList) len() int {
+	return len(l.ranges)
+}
+
+func (l *rangeList) add(r macRange) {
+	i := sort.Search(len(l.ranges), func(i int) bool { return l.ranges[i].lo > r.lo })
+	l.ranges = slices.Insert(l.ranges, i, r)
+	l.maxHi = slices.Insert(l.maxHi, i, 0)
+	for ; i < len(l.ranges); i++ {
+		hi := l.ranges[i].hi
+		if i > 0 {
+			hi = max(hi, l.maxHi[i-1])
+		}
+		l.maxHi[i] = hi
+	}
+}
+
+func (l *rangeList) contains(v uint64) bool {
+	// Find the last range whose lo <= v.
+	i := sort.Search(len(l.ranges), func(i int) bool { return l.ranges[i].lo > v }) - 1
+	return i >= 0 && l.maxHi[i] >= v
+}
+
+// mac48ToUint64 converts a 6-byte MAC to its 48-bit integer value.
+func mac48ToUint64(mac []byte) uint64 {
+	var b [8]byte
+	copy(b[2:], mac[:6])
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// uint64ToMAC48 is the inverse of mac48ToUint64.
+func uint64ToMAC48(v uint64) [6]byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return [6]byte(b[2:])
+}
+
+// cutRange splits a range pattern "lo-hi" into its endpoints.
+// Because "-" is also a MAC separator, a pattern is a range only if it has
+// exactly one dash (colon or dot notation endpoints) or eleven dashes (dash
+// notation endpoints).
+func cutRange(s string) (lo, hi string, ok bool) {
+	switch strings.Count(s, "-") {
+	case 1:
+		lo, hi, _ = strings.Cut(s, "-")
+	case 11:
+		parts := strings.Split(s, "-")
+		lo, hi = strings.Join(parts[:6], "-"), strings.Join(parts[6:], "-")
+	default:
+		return "", "", false
+	}
+	return strings.TrimSpace(lo), strings.TrimSpace(hi), true
+}
+
+// parseRange parses the endpoints of a range pattern.
+func parseRange(lo, hi string) (macRange, error) {
+	loMAC, err := parseMAC(lo)
+	if err != nil {
+		return macRange{}, fmt.Errorf("invalid range start %s: %w", lo, err)
+	}
+	hiMAC, err := parseMAC(hi)
+	if err != nil {
+		return macRange{}, fmt.Errorf("invalid range end %s: %w", hi, err)
+	}
+	if len(loMAC) != len(hiMAC) {
+		return macRange{}, fmt.Errorf("range endpoints have different lengths, %d and %d", len(loMAC), len(hiMAC))
+	}
+	if len(loMAC) != 6 {
+		return macRange{}, fmt.Errorf("invalid range %s-%s: %w", lo, hi, eui48LenError(len(loMAC)))
+	}
+	r := macRange{lo: mac48ToUint64(loMAC), hi: mac48ToUint64(hiMAC)}
+	if r.hi < r.lo {
+		return macRange{}, fmt.Errorf("invalid range %s-%s, end is less than start", lo, hi)
+	}
+	return r, nil
+}
@@ -0,0 +1,95 @@
+package macaddr
+
+import (
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestMatcher_Range(t *testing.T) {
+	m := newTestMatcher(t,
+		"00:11:22:33:44:00-00:11:22:33:44:ff",
+		"00-11-22-33-40-00-00-11-22-33-4f-ff", // dash notation, overlaps the first
+		"aa:00:00:00:00:00-aa:00:00:00:00:10",
+		"bb:00:00:00:00:01-bb:00:00:00:00:01", // collapsed to an exact entry
+	)
+	if m.Len() != 4 || len(m.macs) != 1 {
+		t.Errorf("Len() = %d, exact = %d", m.Len(), len(m.macs))
+	}
+
+	tests := []struct {
+		mac  string
+		want bool
+	}{
+		{"00:11:22:33:44:00", true},
+		{"00:11:22:33:44:80", true},
+		{"00:11:22:33:44:ff", true},
+		{"00:11:22:33:40:00", true},
+		{"00:11:22:33:4f:ff", true},
+		{"00:11:22:33:50:00", false},
+		{"00:11:22:33:3f:ff", false},
+		{"aa:00:00:00:00:10", true},
+		{"aa:00:00:00:00:11", false},
+		{"bb:00:00:00:00:01", true},
+		{"bb:00:00:00:00:02", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(mustParseMAC(tt.mac)); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.mac, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{
+		"00:11:22:33:44:ff-00:11:22:33:44:00",       // hi < lo
+		"00:11:22:33:44:00-00:11:22:33:44:55:66:77", // length mismatch
+		"00:11:22:33:44:00:11:22-00:11:22:33:44:00:11:ff",
+		"00:11:22:33:44:00-zz",
+	} {
+		if err := NewMatcher().Add(s, struct{}{}); err == nil {
+			t.Errorf("Add(%s) should fail", s)
+		}
+	}
+}
+
+// A nested range must not hide an outer range that still covers the address.
+func TestMatcher_RangeNested(t *testing.T) {
+	m := newTestMatcher(t,
+		"00:00:00:00:00:00-00:00:00:00:00:ff",
+		"00:00:00:00:00:10-00:00:00:00:00:20",
+	)
+	if !m.Match(mustParseMAC("00:00:00:00:00:30")) {
+		t.Error("address covered by the outer range should match")
+	}
+}
+
+func benchmarkMatcher(b *testing.B, m *Matcher, n int) {
+	macs := make([][]byte, n)
+	for i := range macs {
+		key := uint64ToMAC48(uint64(i) * 7)
+		macs[i] = key[:]
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Match(macs[i%n])
+	}
+}
+
+func BenchmarkMatcher_MatchExact(b *testing.B) {
+	m := NewMatcher()
+	for i := 0; i < 1000; i++ {
+		key := uint64ToMAC48(uint64(i) * 7)
+		m.add(key[:])
+	}
+	benchmarkMatcher(b, m, 1000)
+}
+
+func BenchmarkMatcher_MatchRange(b *testing.B) {
+	m := NewMatcher()
+	for i := 0; i < 1000; i++ {
+		lo, hi := uint64ToMAC48(uint64(i)*7), uint64ToMAC48(uint64(i)*7+3)
+		if err := m.Add(fmt.Sprintf("%s-%s", net.HardwareAddr(lo[:]), net.HardwareAddr(hi[:])), struct{}{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	benchmarkMatcher(b, m, 1000)
+}
@@ -0,0 +1,97 @@
+package macaddr
+
+import (
+	"fmt"
+	"net"
+)
+
+// MatchRaw is like Match for a raw EUI-48 address. b must be exactly 6
+// bytes, otherwise it does not match. It does not allocate, so together
+// with ParseMACInto it suits hot paths that start from a string.
+func (m *Matcher) MatchRaw(b []byte) bool {
+	if len(b) != 6 {
+		return false
+	}
+	return m.MatchKey([6]byte(b))
+}
+
+// MatchKey is like Match for an EUI-48 address held as a map key, e.g. one
+// returned by Entries, which skips the length check and the conversion.
+func (m *Matcher) MatchKey(key [6]byte) bool {
+	hit := m.matchKey(key)
+	if m.observer != nil {
+		m.observer.ObserveMatch(hit)
+	}
+	return hit
+}
+
+func (m *Matcher) matchKey(key [6]byte) bool {
+	// Prefix-only lists have no exact entries, skip the lookup.
+	if len(m.macs) > 0 && (m.bloom == nil || m.bloom.mayContain(key)) {
+		if _, found := m.macs[key]; found {
+			return true
+		}
+	}
+	return m.matchPattern48(key[:])
+}
+
+// ParseMACInto parses an EUI-48 address in colon, dash or dot notation,
+// e.g. "aa:bb:cc:dd:ee:ff", "aa-bb-cc-dd-ee-ff" or "aabb.ccdd.eeff", into
+// dst. Unlike net.ParseMAC, it does not allocate on success.
+func ParseMACInto(dst *[6]byte, s string) error {
+	var out [6]byte
+	switch len(s) {
+	case 17:
+		sep := s[2]
+		if sep != ':' && sep != '-' {
+			return fmt.Errorf("invalid MAC address %s", s)
+		}
+		for i := range out {
+			if i > 0 && s[3*i-1] != sep {
+				return fmt.Errorf("invalid MAC address %s", s)
+			}
+			b, ok := hexByte(s[3*i], s[3*i+1])
+			if !ok {
+				return fmt.Errorf("invalid MAC address %s", s)
+			}
+			out[i] = b
+		}
+	case 14:
+		for i := range out {
+			j := 5*(i/2) + 2*(i%2)
+			if i%2 == 0 && i > 0 && s[j-1] != '.' {
+				return fmt.Errorf("invalid MAC address %s", s)
+			}
+			b, ok := hexByte(s[j], s[j+1])
+			if !ok {
+				return fmt.Errorf("invalid MAC address %s", s)
+			}
+			out[i] = b
+		}
+	default:
+		if mac, err := net.ParseMAC(s); err == nil {
+			return fmt.Errorf("invalid MAC address %s: %w", s, eui48LenError(len(mac)))
+		}
+		return fmt.Errorf("invalid MAC address %s", s)
+	}
+	*dst = out
+	return nil
+}
+
+func hexByte(hi, lo byte) (byte, bool) {
+	h, ok1 := hexDigit(hi)
+	l, ok2 := hexDigit(lo)
+	return h<<4 | l, ok1 && ok2
+}
+
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
@@ -0,0 +1,130 @@
+package macaddr
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseMACInto(t *testing.T) {
+	tests := []struct {
+		s       string
+		wantErr bool
+	}{
+		{"aa:bb:cc:dd:ee:ff", false},
+		{"AA-BB-CC-DD-EE-0F", false},
+		{"aabb.ccdd.ee0f", false},
+		{"aa:bb-cc:dd:ee:ff", true},
+		{"aa:bb:cc:dd:ee:ff:", true},
+		{"aa:bb:cc:dd:ee:gg", true},
+		{"aabb:ccdd.ee0f", true},
+		{"aa:bb:cc:dd:ee:ff:00:11", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		var got [6]byte
+		err := ParseMACInto(&got, tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMACInto(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		want, err := net.ParseMAC(tt.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if net.HardwareAddr(got[:]).String() != want.String() {
+			t.Errorf("ParseMACInto(%q) = %s, want %s", tt.s, net.HardwareAddr(got[:]), want)
+		}
+	}
+}
+
+func TestMatcher_MatchRaw(t *testing.T) {
+	m := newTestMatcher(t, "00:11:22:33:44:55", "aa:bb:cc")
+	var key [6]byte
+	if err := ParseMACInto(&key, "aa:bb:cc:00:00:01"); err != nil {
+		t.Fatal(err)
+	}
+	if !m.MatchRaw(key[:]) || !m.MatchRaw([]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}) {
+		t.Error("MatchRaw should match")
+	}
+	if m.MatchRaw([]byte{0x00, 0x11, 0x22, 0x33, 0x44}) || m.MatchRaw(mustParseMAC("00:11:22:33:44:55:66:77")) {
+		t.Error("MatchRaw should only accept 6 bytes")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = ParseMACInto(&key, "00:11:22:33:44:55")
+		m.MatchRaw(key[:])
+	})
+	if allocs != 0 {
+		t.Errorf("ParseMACInto and MatchRaw allocate %v times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkMatcher_MatchRaw(b *testing.B) {
+	m := newTestMatcher(b, "00:11:22:33:44:55")
+	raw := []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !m.MatchRaw(raw) {
+			b.Fatal("should match")
+		}
+	}
+}
+
+func TestMatcher_MatchKey(t *testing.T) {
+	m := newTestMatcher(t, "00:11:22:33:44:55", "aa:bb:cc")
+	tests := []struct {
+		key  [6]byte
+		want bool
+	}{
+		{[6]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}, true},
+		{[6]byte{0xaa, 0xbb, 0xcc, 0x00, 0x00, 0x01}, true},
+		{[6]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x56}, false},
+	}
+	for _, tt := range tests {
+		if got := m.MatchKey(tt.key); got != tt.want {
+			t.Errorf("MatchKey(%s) = %v, want %v", FormatMAC(tt.key), got, tt.want)
+		}
+		if got := m.Match(tt.key[:]); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", FormatMAC(tt.key), got, tt.want)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { m.MatchKey(tests[0].key) }); allocs != 0 {
+		t.Errorf("MatchKey allocates %v times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkMatcher_MatchKey(b *testing.B) {
+	m := newTestMatcher(b, "00:11:22:33:44:55")
+	key := [6]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !m.MatchKey(key) {
+			b.Fatal("should match")
+		}
+	}
+}
+
+func BenchmarkMatcher_MatchHit(b *testing.B) {
+	m := newTestMatcher(b, "00:11:22:33:44:55")
+	mac := net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !m.Match(mac) {
+			b.Fatal("should match")
+		}
+	}
+}
+
+func BenchmarkParseMACInto(b *testing.B) {
+	var key [6]byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := ParseMACInto(&key, "aa:bb:cc:dd:ee:ff"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package macaddr
+
+import "fmt"
+
+// MatchSink receives the entries of a DynamicMatcher after each update,
+// e.g. to program them into a kernel set, see NftSetSink.
+type MatchSink interface {
+	// Sync replaces the entries of the sink with macs, which is the full
+	// current set of EUI-48 entries, sorted.
+	Sync(macs [][6]byte) error
+}
+
+// syncSink syncs sink with the entries of m. Matchers that do not expose
+// their entries, e.g. a trie, cannot be synced.
+func syncSink(sink MatchSink, m LocalMatcher) error {
+	em, ok := m.(entriesMatcher)
+	if !ok {
+		return fmt.Errorf("%s matcher does not list its entries", m.Kind())
+	}
+	return sink.Sync(em.Entries())
+}
+
+// sinkDiff returns the entries of macs that are not in cur, and the entries
+// of cur that are not in macs.
+func sinkDiff(cur map[[6]byte]struct{}, macs [][6]byte) (added, removed [][6]byte) {
+	next := make(map[[6]byte]struct{}, len(macs))
+	for _, k := range macs {
+		next[k] = struct{}{}
+		if _, ok := cur[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	for k := range cur {
+		if _, ok := next[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	return added, removed
+}
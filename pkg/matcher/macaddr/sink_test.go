@@ -0,0 +1,90 @@
+package macaddr
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type fakeSink struct {
+	syncs [][][6]byte
+	err   error
+}
+
+func (s *fakeSink) Sync(macs [][6]byte) error {
+	s.syncs = append(s.syncs, macs)
+	return s.err
+}
+
+func TestDynamicMatcher_Sink(t *testing.T) {
+	sink := new(fakeSink)
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	d.Sink = sink
+
+	a := [6]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	b := [6]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x66}
+	c := [6]byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	if err := d.Update([]byte("00:11:22:33:44:66\n00:11:22:33:44:55\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Update([]byte("not a mac\n")); err == nil {
+		t.Fatal("Update() should fail on invalid data")
+	}
+	if err := d.Update([]byte("00:11:22:33:44:55\naa:bb:cc:dd:ee:ff\n")); err != nil {
+		t.Fatal(err)
+	}
+	want := [][][6]byte{{a, b}, {a, c}}
+	if !reflect.DeepEqual(sink.syncs, want) {
+		t.Errorf("sink got %v, want %v", sink.syncs, want)
+	}
+
+}
+
+func TestDynamicMatcher_SinkRollback(t *testing.T) {
+	sink := new(fakeSink)
+	d := NewDynamicMatcher(func(b []byte) (LocalMatcher, error) {
+		return ParseTextMacFile(b)
+	})
+	d.Sink = sink
+	if err := d.Update([]byte("00:11:22:33:44:55\naa:bb:cc:dd:ee:ff\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	sink.err = errors.New("sink down")
+	var hookErr error
+	d.OnUpdate = func(_ int, err error) { hookErr = err }
+	if err := d.Update([]byte("aa:bb:cc:dd:ee:ff\n")); !errors.Is(err, sink.err) {
+		t.Errorf("Update() error = %v, want %v", err, sink.err)
+	}
+	if !errors.Is(hookErr, sink.err) {
+		t.Errorf("OnUpdate error = %v, want %v", hookErr, sink.err)
+	}
+	if !d.Match(mustParseMAC("00:11:22:33:44:55")) {
+		t.Error("previous data should be restored if the sink fails")
+	}
+	if d.Len() != 2 {
+		t.Errorf("Len() = %d, want 2 of the previous data", d.Len())
+	}
+}
+
+func TestSinkDiff(t *testing.T) {
+	a := [6]byte{1}
+	b := [6]byte{2}
+	c := [6]byte{3}
+	cur := map[[6]byte]struct{}{a: {}, b: {}}
+	added, removed := sinkDiff(cur, [][6]byte{b, c})
+	if !slices.Equal(added, [][6]byte{c}) {
+		t.Errorf("added = %v, want %v", added, [][6]byte{c})
+	}
+	if !slices.Equal(removed, [][6]byte{a}) {
+		t.Errorf("removed = %v, want %v", removed, [][6]byte{a})
+	}
+
+	added, removed = sinkDiff(nil, [][6]byte{b, a})
+	if !slices.Equal(added, [][6]byte{b, a}) || len(removed) != 0 {
+		t.Errorf("sinkDiff(nil) = %v, %v, want all added", added, removed)
+	}
+}
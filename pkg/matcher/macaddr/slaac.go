@@ -0,0 +1,31 @@
+package macaddr
+
+import (
+	"net"
+)
+
+// MACFromSLAAC extracts the MAC address embedded in the modified EUI-64
+// interface id of a SLAAC IPv6 address, as described in RFC 4291 appendix A.
+// ok is false if addr is not an IPv6 address or its interface id does not
+// carry the ff:fe marker.
+func MACFromSLAAC(addr net.IP) (mac net.HardwareAddr, ok bool) {
+	if len(addr) != net.IPv6len || addr.To4() != nil {
+		return nil, false
+	}
+	iid := addr[8:]
+	if iid[3] != 0xff || iid[4] != 0xfe {
+		return nil, false
+	}
+	// Flip the universal/local bit back.
+	return net.HardwareAddr{iid[0] ^ 0x02, iid[1], iid[2], iid[5], iid[6], iid[7]}, true
+}
+
+// MatchIPv6SLAAC checks if the MAC address embedded in the SLAAC IPv6
+// address addr is in the matcher. See MACFromSLAAC.
+func (m *Matcher) MatchIPv6SLAAC(addr net.IP) bool {
+	mac, ok := MACFromSLAAC(addr)
+	if !ok {
+		return false
+	}
+	return m.Match(mac)
+}
@@ -0,0 +1,39 @@
+package macaddr
+
+import (
+	"net"
+	"testing"
+)
+
+func TestMatcher_MatchIPv6SLAAC(t *testing.T) {
+	m := newTestMatcher(t, "00:11:22:33:44:55", "52:54:00:12:34:56")
+
+	tests := []struct {
+		name    string
+		addr    string
+		wantMAC string
+		want    bool
+	}{
+		{"link local", "fe80::211:22ff:fe33:4455", "00:11:22:33:44:55", true},
+		{"global", "2001:db8::5054:ff:fe12:3456", "52:54:00:12:34:56", true},
+		{"not in matcher", "fe80::211:22ff:fe33:4466", "00:11:22:33:44:66", false},
+		{"no fffe marker", "fe80::211:22aa:bb33:4455", "", false},
+		{"ipv4", "192.168.1.1", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := net.ParseIP(tt.addr)
+			mac, ok := MACFromSLAAC(addr)
+			if ok != (len(tt.wantMAC) > 0) {
+				t.Fatalf("MACFromSLAAC() ok = %v", ok)
+			}
+			if ok && mac.String() != tt.wantMAC {
+				t.Errorf("MACFromSLAAC() = %s, want %s", mac, tt.wantMAC)
+			}
+			if got := m.MatchIPv6SLAAC(addr); got != tt.want {
+				t.Errorf("MatchIPv6SLAAC() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
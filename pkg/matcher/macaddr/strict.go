@@ -0,0 +1,46 @@
+package macaddr
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+// NewMatcherStrict creates a new empty Matcher that rejects addresses
+// which can never be the source of a DNS query: group (multicast)
+// addresses, whose I/G bit, the least significant bit of the first octet,
+// is set, and the broadcast address ff:ff:ff:ff:ff:ff.
+// Only single addresses are checked, patterns are accepted as is.
+func NewMatcherStrict() *Matcher {
+	m := NewMatcher()
+	m.strict = true
+	return m
+}
+
+// checkStrict returns an error if m is strict and mac is not a unicast address.
+func (m *Matcher) checkStrict(mac net.HardwareAddr) error {
+	if !m.strict {
+		return nil
+	}
+	if IsBroadcast(mac) {
+		return fmt.Errorf("%s is the broadcast address", mac)
+	}
+	if MatchMulticast(mac) {
+		return fmt.Errorf("%s is a multicast address, the I/G bit of the first octet is set", mac)
+	}
+	return nil
+}
+
+// IsBroadcast reports whether mac is the broadcast address, whose octets
+// are all 0xff, e.g. ff:ff:ff:ff:ff:ff. A Matcher created by NewMatcher
+// stores and matches it like any other address.
+func IsBroadcast(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && bytes.Count(mac, []byte{0xff}) == len(mac)
+}
+
+// IsZero reports whether mac is the all-zeros address, e.g.
+// 00:00:00:00:00:00, which often stands for an unknown address in logs.
+// Matchers store and match it like any other address.
+func IsZero(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && bytes.Count(mac, []byte{0x00}) == len(mac)
+}
@@ -0,0 +1,66 @@
+package macaddr
+
+import "testing"
+
+func TestNewMatcherStrict(t *testing.T) {
+	tests := []struct {
+		mac        string
+		wantStrict bool // accepted in strict mode
+	}{
+		{"00:11:22:33:44:55", true},
+		{"02:11:22:33:44:55", true}, // locally administered unicast
+		{"01:00:5e:00:00:01", false},
+		{"33:33:00:00:00:01", false},
+		{"ff:ff:ff:ff:ff:ff", false},
+		{"01:00:5e:00:00:01-01:00:5e:00:00:01", false},
+	}
+	for _, tt := range tests {
+		if err := NewMatcherStrict().Add(tt.mac, struct{}{}); (err == nil) != tt.wantStrict {
+			t.Errorf("strict Add(%s) error = %v, want accepted %v", tt.mac, err, tt.wantStrict)
+		}
+		if err := NewMatcher().Add(tt.mac, struct{}{}); err != nil {
+			t.Errorf("default Add(%s) error = %v", tt.mac, err)
+		}
+	}
+}
+
+func TestBroadcastAndZero(t *testing.T) {
+	const broadcast, zero = "ff:ff:ff:ff:ff:ff", "00:00:00:00:00:00"
+	for _, m := range []*Matcher{NewMatcher(), NewMatcherBloom(2)} {
+		for _, s := range []string{broadcast, zero} {
+			if err := m.Add(s, struct{}{}); err != nil {
+				t.Fatalf("Add(%s) error = %v", s, err)
+			}
+		}
+		if !m.Match(mustParseMAC(broadcast)) || !m.Match(mustParseMAC(zero)) {
+			t.Error("broadcast and zero should match as exact entries")
+		}
+		if m.Match(mustParseMAC("00:00:00:00:00:01")) {
+			t.Error("00:00:00:00:00:01 should not match")
+		}
+	}
+
+	tests := []struct {
+		mac           string
+		wantBroadcast bool
+		wantZero      bool
+	}{
+		{broadcast, true, false},
+		{zero, false, true},
+		{"ff:ff:ff:ff:ff:fe", false, false},
+		{"00:00:00:00:00:01", false, false},
+		{"ff:ff:ff:ff:ff:ff:ff:ff", true, false},
+	}
+	for _, tt := range tests {
+		mac := mustParseMAC(tt.mac)
+		if got := IsBroadcast(mac); got != tt.wantBroadcast {
+			t.Errorf("IsBroadcast(%s) = %v, want %v", tt.mac, got, tt.wantBroadcast)
+		}
+		if got := IsZero(mac); got != tt.wantZero {
+			t.Errorf("IsZero(%s) = %v, want %v", tt.mac, got, tt.wantZero)
+		}
+	}
+	if IsBroadcast(nil) || IsZero(nil) {
+		t.Error("empty address is neither broadcast nor zero")
+	}
+}
@@ -0,0 +1,52 @@
+package macaddr
+
+import (
+	"net"
+	"sync"
+)
+
+// SyncMatcher is a Matcher that is safe for concurrent use.
+// Match takes the read lock, Add and Remove take the write lock.
+type SyncMatcher struct {
+	l sync.RWMutex
+	m *Matcher
+}
+
+// NewSyncMatcher creates a new empty SyncMatcher.
+func NewSyncMatcher() *SyncMatcher {
+	return &SyncMatcher{m: NewMatcher()}
+}
+
+func (s *SyncMatcher) Match(mac net.HardwareAddr) bool {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	return s.m.Match(mac)
+}
+
+func (s *SyncMatcher) Len() int {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	return s.m.Len()
+}
+
+func (s *SyncMatcher) Close() error {
+	return nil
+}
+
+// Add adds a pattern to the matcher. See Matcher.Add.
+func (s *SyncMatcher) Add(pattern string, v struct{}) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.m.Add(pattern, v)
+}
+
+// Remove removes a pattern from the matcher. See Matcher.Remove.
+func (s *SyncMatcher) Remove(pattern string) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.m.Remove(pattern)
+}
+
+func (s *SyncMatcher) Kind() string { return "sync" }
+
+var _ LocalWriteableMatcher = (*SyncMatcher)(nil)
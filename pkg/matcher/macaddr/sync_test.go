@@ -0,0 +1,52 @@
+package macaddr
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Run with -race.
+func TestSyncMatcher_Concurrent(t *testing.T) {
+	m := NewSyncMatcher()
+	mac := mustParseMAC("00:11:22:33:44:55")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s := fmt.Sprintf("00:00:00:00:%02x:%02x", i, j)
+				if err := m.Add(s, struct{}{}); err != nil {
+					t.Error(err)
+					return
+				}
+				if j%2 == 0 {
+					if err := m.Remove(s); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Match(mac)
+				m.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m.Len() != 200 {
+		t.Errorf("Len() = %d, want 200", m.Len())
+	}
+	if !m.Match(mustParseMAC("00:00:00:00:03:63")) {
+		t.Error("added MAC should match")
+	}
+	if m.Match(mustParseMAC("00:00:00:00:03:62")) {
+		t.Error("removed MAC should not match")
+	}
+}
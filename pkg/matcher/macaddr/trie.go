@@ -0,0 +1,123 @@
+package macaddr
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// maxTrieBits is the longest prefix a TrieMatcher stores, a full EUI-48.
+const maxTrieBits = 48
+
+// TrieMatcher matches MAC addresses against prefixes of any bit length,
+// e.g. the IEEE MA-L (/24), MA-M (/28) and MA-S (/36) blocks, and reports
+// the tag of the longest matching prefix.
+// TrieMatcher is not synchronized.
+type TrieMatcher struct {
+	root trieNode
+	n    int
+}
+
+type trieNode struct {
+	children [2]*trieNode
+	term     bool // a prefix ends at this node
+	tag      string
+}
+
+// NewTrieMatcher creates a new empty TrieMatcher.
+func NewTrieMatcher() *TrieMatcher {
+	return new(TrieMatcher)
+}
+
+// Add adds prefix, which is 1 to 6 octets with an optional bit length,
+// e.g. "aa:bb:cc" (/24), "aa:bb:cc:d0/28" or "aa:bb:cc:dd:e0/36".
+// Bits after the bit length are ignored. The prefix as written is its tag.
+func (t *TrieMatcher) Add(prefix string) error {
+	return t.AddWithTag(prefix, strings.TrimSpace(prefix))
+}
+
+// AddWithTag is like Add, but sets the tag of the prefix to tag.
+// Adding an existing prefix again replaces its tag.
+func (t *TrieMatcher) AddWithTag(prefix, tag string) error {
+	key, bits, err := parseBitPrefix(prefix)
+	if err != nil {
+		return err
+	}
+	n := &t.root
+	for i := range bits {
+		b := bitAt(key, i)
+		if n.children[b] == nil {
+			n.children[b] = new(trieNode)
+		}
+		n = n.children[b]
+	}
+	if !n.term {
+		t.n++
+	}
+	n.term = true
+	n.tag = tag
+	return nil
+}
+
+// Match reports whether any prefix matches mac.
+func (t *TrieMatcher) Match(mac net.HardwareAddr) bool {
+	_, ok := t.MatchWithTag(mac)
+	return ok
+}
+
+// MatchWithTag returns the tag of the longest prefix that matches mac.
+func (t *TrieMatcher) MatchWithTag(mac net.HardwareAddr) (tag string, ok bool) {
+	n := &t.root
+	for i := 0; i < min(len(mac)*8, maxTrieBits); i++ {
+		n = n.children[bitAt(mac, i)]
+		if n == nil {
+			break
+		}
+		if n.term {
+			tag, ok = n.tag, true
+		}
+	}
+	return tag, ok
+}
+
+// Len returns the number of prefixes.
+func (t *TrieMatcher) Len() int {
+	return t.n
+}
+
+func (t *TrieMatcher) Close() error {
+	return nil
+}
+
+// bitAt returns the i-th bit of b, counting from the most significant bit.
+func bitAt(b []byte, i int) int {
+	return int(b[i/8]>>(7-i%8)) & 1
+}
+
+// parseBitPrefix parses a prefix of TrieMatcher.Add.
+func parseBitPrefix(s string) (key []byte, bits int, err error) {
+	s = strings.TrimSpace(s)
+	addr, length, hasLength := strings.Cut(s, "/")
+	key, err = parsePartialMAC(addr)
+	if err != nil {
+		mac, macErr := net.ParseMAC(addr)
+		if macErr != nil || len(mac) != 6 {
+			return nil, 0, err
+		}
+		key = mac
+	}
+	bits = len(key) * 8
+	if hasLength {
+		n, err := strconv.Atoi(length)
+		if err != nil || n < 1 || n > bits {
+			return nil, 0, fmt.Errorf("invalid prefix length in %s, expect 1-%d", s, bits)
+		}
+		bits = n
+	}
+	return key, bits, nil
+}
+
+func (t *TrieMatcher) Kind() string { return "trie" }
+
+var _ LocalMatcher = (*TrieMatcher)(nil)
@@ -0,0 +1,54 @@
+package macaddr
+
+import "testing"
+
+func TestTrieMatcher(t *testing.T) {
+	m := NewTrieMatcher()
+	for _, p := range []string{"aa:bb:cc", "aa:bb:cc:d0/28", "aa:bb:cc:dd:e0/36"} {
+		if err := m.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.AddWithTag("00:11:22:33:44:55", "laptop"); err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", m.Len())
+	}
+
+	tests := []struct {
+		mac    string
+		wantOk bool
+		tag    string
+	}{
+		{"aa:bb:cc:dd:ee:ff", true, "aa:bb:cc:dd:e0/36"}, // /36 wins over /28 and /24
+		{"aa:bb:cc:dd:f0:00", true, "aa:bb:cc:d0/28"},
+		{"aa:bb:cc:df:00:00", true, "aa:bb:cc:d0/28"},
+		{"aa:bb:cc:e0:00:00", true, "aa:bb:cc"},
+		{"aa:bb:cc:dd:ee:ff:00:11", true, "aa:bb:cc:dd:e0/36"},
+		{"00:11:22:33:44:55", true, "laptop"},
+		{"00:11:22:33:44:56", false, ""},
+		{"aa:bb:cd:00:00:00", false, ""},
+	}
+	for _, tt := range tests {
+		tag, ok := m.MatchWithTag(mustParseMAC(tt.mac))
+		if ok != tt.wantOk || tag != tt.tag {
+			t.Errorf("MatchWithTag(%s) = %q, %v, want %q, %v", tt.mac, tag, ok, tt.tag, tt.wantOk)
+		}
+	}
+
+	// Bits after the length are ignored.
+	m2 := NewTrieMatcher()
+	if err := m2.Add("aa:bb:cc:dd/28"); err != nil {
+		t.Fatal(err)
+	}
+	if !m2.Match(mustParseMAC("aa:bb:cc:d1:00:00")) {
+		t.Error("aa:bb:cc:dd/28 should cover aa:bb:cc:d1:00:00")
+	}
+
+	for _, p := range []string{"aa:bb:cc/25", "aa:bb:cc/0", "aa:bb:cc/x", "zz:bb:cc", ""} {
+		if err := NewTrieMatcher().Add(p); err == nil {
+			t.Errorf("Add(%q) should fail", p)
+		}
+	}
+}
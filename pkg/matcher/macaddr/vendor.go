@@ -0,0 +1,171 @@
+package macaddr
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// oui.csv is only embedded with the oui_db build tag, see EmbeddedOUIRegistry.
+// The committed file is a trimmed snapshot, go generate replaces it with
+// the full registry.
+//go:generate curl -fsSL -o oui.csv https://standards-oui.ieee.org/oui/oui.csv
+
+// OUIRegistry maps vendor names to their OUIs.
+type OUIRegistry struct {
+	vendors map[string][][3]byte
+}
+
+// ParseOUIRegistry parses the IEEE MA-L registry in CSV format, e.g.
+// https://standards-oui.ieee.org/oui/oui.csv, whose columns are
+// "Registry,Assignment,Organization Name,Organization Address".
+// Rows of other registries, e.g. MA-M and MA-S, are skipped, since their
+// assignments are longer than an OUI. Malformed rows are skipped too, so a
+// single bad row of the large registry does not make it unusable, but it
+// returns an error if r has no assignment.
+func ParseOUIRegistry(r io.Reader) (*OUIRegistry, error) {
+	reg := &OUIRegistry{vendors: make(map[string][][3]byte)}
+	err := readOUICSV(r, func(int, error) {}, func(oui [3]byte, name string) {
+		reg.vendors[name] = append(reg.vendors[name], oui)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reg.vendors) == 0 {
+		return nil, errors.New("no OUI assignment found")
+	}
+	return reg, nil
+}
+
+// ParseIEEEOUICSV parses the IEEE MA-L registry in CSV format, see
+// ParseOUIRegistry, into a Matcher that holds each assignment as a prefix,
+// so it matches all addresses of the registered vendors. The header and
+// malformed rows are skipped, but it returns an error if in has no
+// assignment.
+func ParseIEEEOUICSV(in []byte) (*Matcher, error) {
+	m := NewMatcher()
+	err := readOUICSV(bytes.NewReader(in), func(int, error) {}, func(oui [3]byte, _ string) {
+		m.addPrefix(oui)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m.Len() == 0 {
+		return nil, errors.New("no OUI assignment found")
+	}
+	return m, nil
+}
+
+// readOUICSV calls fn for each MA-L row of the IEEE registry CSV in r.
+// The header and the rows of other registries are skipped. If skip is
+// nil, a malformed row stops the read with an error, otherwise skip is
+// called with its line and the read continues.
+func readOUICSV(r io.Reader, skip func(line int, err error), fn func(oui [3]byte, name string)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var pe *csv.ParseError
+		if err != nil && !errors.As(err, &pe) {
+			return err
+		}
+		var oui [3]byte
+		ok := false
+		if err == nil {
+			oui, ok, err = parseOUIRow(rec)
+		}
+		if err != nil {
+			err = fmt.Errorf("line %d: %w", line, err)
+			if skip == nil {
+				return err
+			}
+			skip(line, err)
+			continue
+		}
+		if ok {
+			fn(oui, strings.TrimSpace(rec[2]))
+		}
+	}
+}
+
+// parseOUIRow parses a row of the IEEE registry CSV. ok is false for the
+// header and the rows of other registries than MA-L.
+func parseOUIRow(rec []string) (oui [3]byte, ok bool, err error) {
+	if len(rec) < 3 {
+		return oui, false, fmt.Errorf("expect at least 3 columns, got %d", len(rec))
+	}
+	if rec[0] != "MA-L" {
+		return oui, false, nil
+	}
+	b, err := hex.DecodeString(rec[1])
+	if err != nil || len(b) != 3 {
+		return oui, false, fmt.Errorf("invalid assignment %q", rec[1])
+	}
+	return [3]byte(b), true, nil
+}
+
+// VendorMatcher matches MAC addresses by the vendor name of their OUI.
+type VendorMatcher struct {
+	reg  *OUIRegistry
+	ouis map[[3]byte]struct{}
+}
+
+// NewVendorMatcher creates an empty VendorMatcher that looks up vendors
+// in reg.
+func NewVendorMatcher(reg *OUIRegistry) *VendorMatcher {
+	return &VendorMatcher{reg: reg, ouis: make(map[[3]byte]struct{})}
+}
+
+// Add adds the OUIs of all vendors whose name contains vendor, ignoring
+// case, e.g. "Apple" adds the OUIs of "Apple, Inc.". It returns an error
+// if no vendor matches.
+func (v *VendorMatcher) Add(vendor string) error {
+	sub := strings.ToLower(strings.TrimSpace(vendor))
+	if len(sub) == 0 {
+		return errors.New("empty vendor name")
+	}
+	found := false
+	for name, ouis := range v.reg.vendors {
+		if !strings.Contains(strings.ToLower(name), sub) {
+			continue
+		}
+		found = true
+		for _, oui := range ouis {
+			v.ouis[oui] = struct{}{}
+		}
+	}
+	if !found {
+		return fmt.Errorf("no vendor matches %q", vendor)
+	}
+	return nil
+}
+
+func (v *VendorMatcher) Match(mac net.HardwareAddr) bool {
+	if len(mac) != 6 && len(mac) != 8 {
+		return false
+	}
+	_, ok := v.ouis[[3]byte(mac)]
+	return ok
+}
+
+// Len returns the number of OUIs in the matcher.
+func (v *VendorMatcher) Len() int {
+	return len(v.ouis)
+}
+
+func (v *VendorMatcher) Close() error {
+	return nil
+}
+
+func (v *VendorMatcher) Kind() string { return "vendor" }
+
+var _ LocalMatcher = (*VendorMatcher)(nil)
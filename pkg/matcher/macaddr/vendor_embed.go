@@ -0,0 +1,23 @@
+//go:build oui_db
+
+package macaddr
+
+import (
+	"bytes"
+	_ "embed"
+	"sync"
+)
+
+//go:embed oui.csv
+var ouiCSV []byte
+
+var embeddedOUIRegistry = sync.OnceValues(func() (*OUIRegistry, error) {
+	return ParseOUIRegistry(bytes.NewReader(ouiCSV))
+})
+
+// EmbeddedOUIRegistry returns the IEEE OUI registry embedded in the binary.
+// It is only available in builds with the oui_db tag. The committed
+// oui.csv is a trimmed snapshot, run go generate to embed the full registry.
+func EmbeddedOUIRegistry() (*OUIRegistry, error) {
+	return embeddedOUIRegistry()
+}
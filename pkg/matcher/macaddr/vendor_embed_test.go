@@ -0,0 +1,22 @@
+//go:build oui_db
+
+package macaddr
+
+import "testing"
+
+func TestEmbeddedOUIRegistry(t *testing.T) {
+	reg, err := EmbeddedOUIRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVendorMatcher(reg)
+	if err := v.Add("Apple"); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Match(mustParseMAC("f0:18:98:01:02:03")) {
+		t.Error("Apple OUI f0:18:98 should match")
+	}
+	if v.Match(mustParseMAC("b8:27:eb:01:02:03")) {
+		t.Error("Raspberry Pi OUI should not match Apple")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !oui_db
+
+package macaddr
+
+import "errors"
+
+// EmbeddedOUIRegistry returns the IEEE OUI registry embedded in the binary.
+// It is only available in builds with the oui_db tag. The committed
+// oui.csv is a trimmed snapshot, run go generate to embed the full registry.
+func EmbeddedOUIRegistry() (*OUIRegistry, error) {
+	return nil, errors.New("OUI registry is not embedded, build with the oui_db tag")
+}
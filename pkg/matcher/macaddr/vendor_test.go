@@ -0,0 +1,76 @@
+package macaddr
+
+import (
+	"strings"
+	"testing"
+)
+
+const testOUIRegistry = `Registry,Assignment,Organization Name,Organization Address
+MA-L,F01898,"Apple, Inc.",1 Infinite Loop Cupertino CA US 95014 
+MA-L,3C0754,"Apple, Inc.",1 Infinite Loop Cupertino CA US 95014 
+MA-L,F0D5BF,Intel Corporate,Lot 8 Jalan Hi-Tech 2/3  Kulim Kedah MY 09000 
+MA-M,70B3D5000,Example Inc.,Somewhere
+`
+
+func TestVendorMatcher(t *testing.T) {
+	reg, err := ParseOUIRegistry(strings.NewReader(testOUIRegistry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVendorMatcher(reg)
+	if err := v.Add("apple"); err != nil {
+		t.Fatal(err)
+	}
+	if v.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", v.Len())
+	}
+	if !v.Match(mustParseMAC("f0:18:98:01:02:03")) || !v.Match(mustParseMAC("3c:07:54:aa:bb:cc")) {
+		t.Error("Apple OUIs should match")
+	}
+	if v.Match(mustParseMAC("f0:d5:bf:01:02:03")) {
+		t.Error("Intel OUI should not match")
+	}
+
+	if err := v.Add("Example"); err == nil {
+		t.Error("MA-M rows should be skipped")
+	}
+	if _, err := ParseOUIRegistry(strings.NewReader("MA-L,F018,Short\n")); err == nil {
+		t.Error("expected error for a registry without a valid assignment")
+	}
+
+	reg, err = ParseOUIRegistry(strings.NewReader(testOUIRegistry + "MA-L,F018,Short\n"))
+	if err != nil {
+		t.Fatalf("a malformed row should be skipped, %v", err)
+	}
+	if err := NewVendorMatcher(reg).Add("Intel"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseIEEEOUICSV(t *testing.T) {
+	const data = `Registry,Assignment,Organization Name,Organization Address
+MA-L,F01898,"Apple, Inc.",1 Infinite Loop Cupertino CA US 95014 
+MA-L,ZZ0000,Broken Row,
+short row
+MA-L,F0D5BF,Intel Corporate,Lot 8 Jalan Hi-Tech 2/3  Kulim Kedah MY 09000 
+`
+	m, err := ParseIEEEOUICSV([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+	for _, s := range []string{"f0:18:98:01:02:03", "f0:d5:bf:aa:bb:cc"} {
+		if !m.Match(mustParseMAC(s)) {
+			t.Errorf("%s should match", s)
+		}
+	}
+	if m.Match(mustParseMAC("00:11:22:33:44:55")) {
+		t.Error("unregistered OUI should not match")
+	}
+
+	if _, err := ParseIEEEOUICSV([]byte("Registry,Assignment,Organization Name,Organization Address\n")); err == nil {
+		t.Error("expected error for a registry without assignments")
+	}
+}
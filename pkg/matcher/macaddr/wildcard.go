@@ -0,0 +1,60 @@
+package macaddr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// maskedMAC is an EUI-48 pattern with wildcard octets.
+// A MAC matches if mac & mask == value.
+type maskedMAC struct {
+	value [6]byte
+	mask  [6]byte
+}
+
+func (p maskedMAC) match(mac net.HardwareAddr) bool {
+	for i := range p.value {
+		if mac[i]&p.mask[i] != p.value[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isWildcardPattern reports whether s uses the wildcard syntax.
+func isWildcardPattern(s string) bool {
+	return strings.Contains(s, "*")
+}
+
+// parseWildcardMAC parses an EUI-48 pattern whose octets may be "*",
+// e.g. "aa:bb:cc:*:*:*" or "aa-bb-*-dd-ee-ff".
+// net.ParseMAC does not accept wildcards, so this is a separate parser.
+// It only supports the colon and dash notations with 6 octets.
+func parseWildcardMAC(s string) (maskedMAC, error) {
+	var p maskedMAC
+	sep := ":"
+	if !strings.Contains(s, sep) {
+		sep = "-"
+	}
+	octets := strings.Split(s, sep)
+	if len(octets) != 6 {
+		return p, fmt.Errorf("invalid wildcard MAC %s: %w", s, eui48LenError(len(octets)))
+	}
+	for i, o := range octets {
+		if o == "*" {
+			continue
+		}
+		if len(o) != 2 {
+			return p, fmt.Errorf("invalid wildcard MAC %s: octet %q must be 2 hex digits or *", s, o)
+		}
+		b, err := hex.DecodeString(o)
+		if err != nil {
+			return p, fmt.Errorf("invalid wildcard MAC %s: %w", s, err)
+		}
+		p.value[i] = b[0]
+		p.mask[i] = 0xff
+	}
+	return p, nil
+}
@@ -0,0 +1,36 @@
+package macaddr
+
+import (
+	"testing"
+)
+
+func TestMatcher_Wildcard(t *testing.T) {
+	m := newTestMatcher(t, "aa:bb:cc:*:*:*", "11-22-*-44-55-66", "00:00:00:00:00:01")
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+
+	tests := []struct {
+		mac  string
+		want bool
+	}{
+		{"aa:bb:cc:00:00:00", true},
+		{"aa:bb:cc:12:34:56", true},
+		{"aa:bb:cd:12:34:56", false},
+		{"11:22:33:44:55:66", true},
+		{"11:22:ff:44:55:66", true},
+		{"11:22:ff:44:55:67", false},
+		{"00:00:00:00:00:01", true},
+	}
+	for _, tt := range tests {
+		if got := m.Match(mustParseMAC(tt.mac)); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.mac, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{"aa:bb:*:*:*", "aa:bb:c*:*:*:*", "aa:bb:zz:*:*:*", "aa:bb:cc-*:*:*:*"} {
+		if err := NewMatcher().Add(s, struct{}{}); err == nil {
+			t.Errorf("Add(%s) should fail", s)
+		}
+	}
+}
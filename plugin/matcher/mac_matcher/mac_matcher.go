@@ -21,7 +21,10 @@ package mac_matcher
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -55,13 +58,53 @@ func (h *hasMACAddr) Match(_ context.Context, qCtx *query_context.Context) (matc
 // Args contains configuration for the mac_matcher plugin.
 type Args struct {
 	MacAddress []string `yaml:"mac_address"`
+	// Exclude never matches these entries, even if mac_address matches them.
+	Exclude []string `yaml:"exclude"`
+	// Sites only loads entries of these sites. Empty means all sites.
+	Sites []string `yaml:"sites"`
+	// MaxEntries refuses provider and file data with more entries than
+	// this, e.g. a misconfigured huge list. 0 means unlimited.
+	MaxEntries int `yaml:"max_entries"`
+	// GracePeriod keeps entries removed from a provider matching for
+	// this many seconds. 0 disables the grace window.
+	GracePeriod int `yaml:"grace_period"`
+	// DeviceType also matches devices classified into these types.
+	DeviceType []string `yaml:"device_type"`
+	// DeviceRules overrides the built-in device type heuristics.
+	DeviceRules []macaddr.DeviceRule `yaml:"device_rules"`
+	// AllowEmpty allows a matcher without any source. Such a matcher
+	// matches nothing, so it is rejected unless explicitly allowed.
+	AllowEmpty bool `yaml:"allow_empty"`
+	// LogMAC logs the client MAC of every query that carries one.
+	// It is opt-in because MACs identify physical devices.
+	LogMAC bool `yaml:"log_mac"`
+}
+
+func (a *Args) validate() error {
+	if len(a.MacAddress) == 0 && len(a.DeviceType) == 0 && !a.AllowEmpty {
+		return errors.New("no mac_address or device_type configured, set allow_empty to use an empty matcher")
+	}
+	var errs []error
+	if a.MaxEntries < 0 {
+		errs = append(errs, fmt.Errorf("invalid max_entries %d, must not be negative", a.MaxEntries))
+	}
+	for _, err := range macaddr.ValidatePatterns(a.MacAddress) {
+		errs = append(errs, fmt.Errorf("invalid mac_address %w", err))
+	}
+	for _, err := range macaddr.ValidatePatterns(a.Exclude) {
+		errs = append(errs, fmt.Errorf("invalid exclude %w", err))
+	}
+	return errors.Join(errs...)
 }
 
 type macMatcher struct {
 	*coremain.BP
 
-	macMatcher *macaddr.LocalMatcherGroup
-	closer     []io.Closer
+	macMatcher  *macaddr.LocalMatcherGroup
+	classifier  *macaddr.DeviceClassifier
+	deviceTypes map[string]struct{}
+	logMAC      bool
+	closer      []io.Closer
 }
 
 func (m *macMatcher) Match(_ context.Context, qCtx *query_context.Context) (matched bool, err error) {
@@ -69,7 +112,22 @@ func (m *macMatcher) Match(_ context.Context, qCtx *query_context.Context) (matc
 	if mac == nil {
 		return false, nil
 	}
-	return m.macMatcher.Match(mac), nil
+	if m.logMAC {
+		m.L().Info("query client mac", qCtx.InfoField(), zap.Stringer("mac", mac))
+	}
+	switch m.macMatcher.MatchOutcome(mac) {
+	case macaddr.OutcomeHit:
+		return true, nil
+	case macaddr.OutcomeGrace:
+		m.L().Info("mac address matched in grace period", qCtx.InfoField(), zap.Stringer("mac", mac))
+		return true, nil
+	}
+	if m.classifier != nil {
+		// The plugin has no hostname source, so hostname rules see an unknown hostname.
+		_, ok := m.deviceTypes[m.classifier.DeviceType(mac, "")]
+		return ok, nil
+	}
+	return false, nil
 }
 
 func (m *macMatcher) Close() error {
@@ -84,21 +142,60 @@ func Init(bp *coremain.BP, args interface{}) (p coremain.Plugin, err error) {
 }
 
 func newMacMatcher(bp *coremain.BP, args *Args) (*macMatcher, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
 	m := &macMatcher{
-		BP: bp,
+		BP:     bp,
+		logMAC: args.LogMAC,
 	}
 
-	mg, err := macaddr.BatchLoadMacProvider(
+	mg, err := macaddr.BatchLoadMacProviderLimited(
 		args.MacAddress,
 		bp.M().GetDataManager(),
+		args.Sites,
+		args.MaxEntries,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if len(args.Exclude) > 0 {
+		ex, err := macaddr.BatchLoadMacProviderLimited(
+			args.Exclude,
+			bp.M().GetDataManager(),
+			args.Sites,
+			args.MaxEntries,
+		)
+		if err != nil {
+			_ = mg.Close()
+			return nil, fmt.Errorf("failed to load exclude, %w", err)
+		}
+		mg.AppendExclude(ex)
+	}
+	if args.GracePeriod > 0 {
+		mg.SetGracePeriod(time.Duration(args.GracePeriod) * time.Second)
+	}
 	m.macMatcher = mg
 	m.closer = append(m.closer, mg)
 	bp.L().Info("mac address matcher loaded", zap.Int("length", mg.Len()))
 
+	if len(args.DeviceType) > 0 {
+		rules := args.DeviceRules
+		if len(rules) == 0 {
+			rules = macaddr.DefaultDeviceRules()
+		}
+		c, err := macaddr.NewDeviceClassifier(rules)
+		if err != nil {
+			_ = mg.Close()
+			return nil, fmt.Errorf("invalid device rules, %w", err)
+		}
+		m.classifier = c
+		m.deviceTypes = make(map[string]struct{}, len(args.DeviceType))
+		for _, t := range args.DeviceType {
+			m.deviceTypes[t] = struct{}{}
+		}
+	}
+
 	return m, nil
 }
 
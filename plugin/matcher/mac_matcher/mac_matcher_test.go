@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2020-2022, IrineSistiana
+ *
+ * This file is part of mosdns.
+ *
+ * mosdns is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * mosdns is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mac_matcher
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/pmkol/mosdns-x/coremain"
+	"github.com/pmkol/mosdns-x/pkg/matcher/macaddr"
+	"github.com/pmkol/mosdns-x/pkg/query_context"
+)
+
+func TestArgs_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    Args
+		wantErr bool
+	}{
+		{"empty", Args{}, true},
+		{"empty allowed", Args{AllowEmpty: true}, false},
+		{"mac address", Args{MacAddress: []string{"aa:bb:cc:dd:ee:ff"}}, false},
+		{"device type", Args{DeviceType: []string{"mobile"}}, false},
+		{"bad mac address", Args{MacAddress: []string{"aa:bb:cc:dd:ee:ff", "bad"}}, true},
+		{"bad exclude", Args{MacAddress: []string{"provider:macs"}, Exclude: []string{"bad"}}, true},
+		{"negative max entries", Args{MacAddress: []string{"provider:macs"}, MaxEntries: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.args.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := newMacMatcher(coremain.NewBP("test", PluginType, nil, nil), &Args{}); err == nil {
+		t.Error("newMacMatcher() with empty args should fail")
+	}
+}
+
+func TestMacMatcher_LogMAC(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	bp := coremain.NewBP("test", PluginType, zap.New(core), nil)
+	mg, err := macaddr.BatchLoadMacProvider([]string{"aa:bb:cc:dd:ee:ff"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &macMatcher{BP: bp, macMatcher: mg, logMAC: true}
+
+	q := new(dns.Msg)
+	q.SetQuestion("example.com.", dns.TypeA)
+	if _, err := m.Match(context.Background(), query_context.NewContext(q, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if logs.Len() != 0 {
+		t.Fatalf("query without mac should not be logged, got %d entries", logs.Len())
+	}
+
+	mac, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	q.SetEdns0(dns.DefaultMsgSize, false)
+	opt := q.IsEdns0()
+	opt.Option = append(opt.Option, &dns.EDNS0_LOCAL{Code: dns.EDNS0LOCALSTART, Data: mac})
+	matched, err := m.Match(context.Background(), query_context.NewContext(q, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !matched {
+		t.Error("query should match")
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 log entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["mac"] != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("mac field = %v", fields["mac"])
+	}
+	if _, ok := fields["query"]; !ok {
+		t.Error("missing query field")
+	}
+}
@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pmkol/mosdns-x/coremain"
+	"github.com/pmkol/mosdns-x/mlog"
+	"github.com/pmkol/mosdns-x/pkg/matcher/macaddr"
+)
+
+func init() {
+	macCmd := &cobra.Command{
+		Use:   "mac",
+		Short: "Tools for MAC address lists.",
+	}
+	macCmd.AddCommand(newMacOverlapCmd(), newMacTestCmd(), newMacCompileCmd())
+	coremain.AddSubCmd(macCmd)
+}
+
+func newMacOverlapCmd() *cobra.Command {
+	var maxSample int
+	c := &cobra.Command{
+		Use:   "overlap [-s max_sample] list_file...",
+		Args:  cobra.MinimumNArgs(2),
+		Short: "Print the pairwise overlap counts of MAC address list files.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := MacOverlap(args, maxSample); err != nil {
+				mlog.S().Fatal(err)
+			}
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().IntVarP(&maxSample, "sample", "s", 0, "max entries to examine per pair, 0 means all")
+	return c
+}
+
+// MacOverlap prints the overlap matrix of MAC list files to stdout.
+func MacOverlap(files []string, maxSample int) error {
+	ms := make([]*macaddr.Matcher, 0, len(files))
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		m, err := macaddr.ParseTextMacFile(b)
+		if err != nil {
+			return fmt.Errorf("failed to load %s, %w", file, err)
+		}
+		ms = append(ms, m)
+	}
+
+	res := macaddr.OverlapMatrix(ms, maxSample)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, file := range files {
+		fmt.Fprintf(w, "\t%s", fileName(file))
+	}
+	fmt.Fprintln(w, "\t")
+	for i, row := range res {
+		fmt.Fprintf(w, "%s", fileName(files[i]))
+		for _, n := range row {
+			fmt.Fprintf(w, "\t%d", n)
+		}
+		fmt.Fprintln(w, "\t")
+	}
+	return w.Flush()
+}
+
+func newMacTestCmd() *cobra.Command {
+	var (
+		lists  []string
+		format string
+	)
+	c := &cobra.Command{
+		Use:   "test -l name=list_file... [-f csv|json] input_file",
+		Args:  cobra.ExactArgs(1),
+		Short: "Report which MAC address lists match each MAC in input_file.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := MacTest(lists, args[0], format); err != nil {
+				mlog.S().Fatal(err)
+			}
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().StringArrayVarP(&lists, "list", "l", nil, "list to test against, as name=file, can be repeated")
+	c.Flags().StringVarP(&format, "format", "f", "csv", "report format, csv or json")
+	c.MarkFlagRequired("list")
+	return c
+}
+
+// MacTest evaluates the MACs in input against lists and prints the report to stdout.
+func MacTest(lists []string, input, format string) error {
+	ms := make([]macaddr.NamedMatcher, 0, len(lists))
+	for _, l := range lists {
+		name, file, ok := strings.Cut(l, "=")
+		if !ok {
+			name, file = fileName(l), l
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		m, err := macaddr.ParseTextMacFile(b)
+		if err != nil {
+			return fmt.Errorf("failed to load %s, %w", file, err)
+		}
+		ms = append(ms, macaddr.NamedMatcher{Name: name, Matcher: m})
+	}
+
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res, err := macaddr.Evaluate(f, ms)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "csv":
+		return macaddr.WriteEvaluateCSV(os.Stdout, res)
+	case "json":
+		return macaddr.WriteEvaluateJSON(os.Stdout, res)
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+func newMacCompileCmd() *cobra.Command {
+	var output string
+	c := &cobra.Command{
+		Use:   "compile -o output_file list_file",
+		Args:  cobra.ExactArgs(1),
+		Short: "Compile a text MAC address list to the binary format, which loads faster.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := MacCompile(args[0], output); err != nil {
+				mlog.S().Fatal(err)
+			}
+		},
+		DisableFlagsInUseLine: true,
+	}
+	c.Flags().StringVarP(&output, "output", "o", "", "output binary file")
+	c.MarkFlagRequired("output")
+	return c
+}
+
+// MacCompile compiles the text MAC list file input to the binary file output.
+func MacCompile(input, output string) error {
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	n, err := macaddr.CompileTextToBinary(in, out)
+	if err == nil {
+		err = out.Close()
+	} else {
+		out.Close()
+	}
+	if err != nil {
+		os.Remove(output)
+		return fmt.Errorf("failed to compile %s, %w", input, err)
+	}
+	mlog.S().Infof("compiled %d entries to %s", n, output)
+	return nil
+}